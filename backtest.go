@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"stockMaxWin/internal/backtest"
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/trace"
+)
+
+// backtest 命令默认持有日与超时：全市场逐只拉几百根 K 线，受限流约束需要较长时间
+const (
+	defaultBacktestHoldDays = 5
+	backtestTimeout         = 30 * time.Minute
+	backtestDateLayout      = "2006-01-02"
+)
+
+// runBacktestCommand 用当前生效阈值的趋势动能策略回放 起始日 至今，输出入选后持有 N 日的平均收益与胜率。
+// 股票池取本轮扫描范围的当前行情（见 STOCKMAXWIN_BOARDS / STOCKMAXWIN_SCAN_MODE）。
+// 用法：stockMaxWin backtest <起始日 YYYY-MM-DD> [持有日=5]
+func runBacktestCommand(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "用法: stockMaxWin backtest <起始日 YYYY-MM-DD> [持有日=5]")
+		return 2
+	}
+	start, err := time.ParseInLocation(backtestDateLayout, args[0], time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "起始日无效: %v\n", err)
+		return 2
+	}
+	hold := intArg(args, 1, defaultBacktestHoldDays)
+
+	ctx, cancel := context.WithTimeout(context.Background(), backtestTimeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	ctx = trace.WithTraceID(ctx, trace.NewTraceID())
+	quotes, err := fetchQuotes(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "拉取%s行情失败: %v\n", scanLabel(), err)
+		return 1
+	}
+	strategy := filter.TrendMomentumStrategyFrom(strategyThresholds(ctx))
+	res, err := backtest.RunQuotes(ctx, apiClient, quotes, strategy, start, hold)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "回测失败: %v\n", err)
+		return 1
+	}
+	fmt.Printf("趋势动能策略 %s 起，%s %d 只，入选后持有 %d 日：样本 %d，平均收益 %.2f%%，胜率 %.1f%%\n",
+		args[0], scanLabel(), len(quotes), hold, res.Count, res.AvgReturn, res.WinRate)
+	return 0
+}
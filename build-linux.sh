@@ -3,6 +3,10 @@
 
 set -e
 OUT="stockMaxWin-linux-amd64"
-echo "Building for linux/amd64 -> $OUT"
-GOOS=linux GOARCH=amd64 go build -trimpath -ldflags "-s -w" -o "$OUT" .
+BUILDINFO="stockMaxWin/internal/buildinfo"
+VERSION=$(git describe --tags --always --dirty 2>/dev/null || echo dev)
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+BUILDTIME=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+echo "Building for linux/amd64 -> $OUT ($VERSION $COMMIT)"
+GOOS=linux GOARCH=amd64 go build -trimpath -ldflags "-s -w -X $BUILDINFO.Version=$VERSION -X $BUILDINFO.Commit=$COMMIT -X $BUILDINFO.BuildTime=$BUILDTIME" -o "$OUT" .
 echo "Done: $OUT"
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"stockMaxWin/internal/history"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 入选冷却期：同一代码 STOCKMAXWIN_COOLDOWN_DAYS 天内最多推一次（依赖 STOCKMAXWIN_HISTORY_FILE）。
+// STOCKMAXWIN_COOLDOWN_MODE=mark 时冷却中的股票仍推送但标注“冷却中”，默认 skip 直接不推。
+const (
+	envCooldownDays  = "STOCKMAXWIN_COOLDOWN_DAYS"
+	envCooldownMode  = "STOCKMAXWIN_COOLDOWN_MODE"
+	cooldownModeMark = "mark"
+)
+
+func cooldownDays() int {
+	if s := os.Getenv(envCooldownDays); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// applyCooldown 按入选历史判断冷却：近 days 天内已送达过（history.Record.Pushed，不含当时已处于冷却的记录）的代码视为冷却中；
+// 只入选未送达（未配置推送渠道或全部渠道失败）的记录不计入。
+// mark 模式下标注 Stock.Cooldown 并保留，否则剔除。未开启或历史不可读时原样返回。
+func applyCooldown(ctx context.Context, stocks []*model.Stock) []*model.Stock {
+	days := cooldownDays()
+	path := os.Getenv(envHistoryFile)
+	if days == 0 || path == "" || len(stocks) == 0 {
+		return stocks
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		trace.Log(ctx, "main: 冷却期读取入选历史失败，跳过 err=%v", err)
+		return stocks
+	}
+	since := clock().AddDate(0, 0, -days)
+	pushed := make(map[string]bool)
+	for _, r := range records {
+		if r.Pushed && !r.Cooldown && r.RunAt.After(since) {
+			pushed[r.Code] = true
+		}
+	}
+	mark := strings.EqualFold(strings.TrimSpace(os.Getenv(envCooldownMode)), cooldownModeMark)
+	out := stocks[:0]
+	cooling := 0
+	for _, s := range stocks {
+		if !pushed[s.Code] {
+			out = append(out, s)
+			continue
+		}
+		cooling++
+		if mark {
+			s.Cooldown = true
+			out = append(out, s)
+		}
+	}
+	trace.Log(ctx, "main: 冷却期 %d 天，冷却中 %d 只（mark=%v）", days, cooling, mark)
+	return out
+}
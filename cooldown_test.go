@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stockMaxWin/internal/history"
+	"stockMaxWin/internal/model"
+)
+
+// 冷却期只看送达过的入选：未送达（无渠道或全部失败）与当时已在冷却中的记录不计入，超出天数的记录不计入。
+func TestApplyCooldownOnlyDelivered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	t.Setenv(envHistoryFile, path)
+	t.Setenv(envCooldownDays, "3")
+	t.Setenv(envCooldownMode, "")
+
+	now := time.Now()
+	write := func(runAt time.Time, s model.Stock) {
+		t.Helper()
+		if err := history.Append(path, runAt, "t", []*model.Stock{&s}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(now.Add(-time.Hour), model.Stock{Code: "600001", Delivered: true})
+	write(now.Add(-time.Hour), model.Stock{Code: "600002"})
+	write(now.Add(-time.Hour), model.Stock{Code: "600003", Delivered: true, Cooldown: true})
+	write(now.AddDate(0, 0, -5), model.Stock{Code: "600004", Delivered: true})
+
+	stocks := []*model.Stock{{Code: "600001"}, {Code: "600002"}, {Code: "600003"}, {Code: "600004"}, {Code: "600005"}}
+	got := applyCooldown(context.Background(), stocks)
+	want := []string{"600002", "600003", "600004", "600005"}
+	if len(got) != len(want) {
+		t.Fatalf("applyCooldown 保留 %d 只，want %d", len(got), len(want))
+	}
+	for i, s := range got {
+		if s.Code != want[i] {
+			t.Errorf("[%d] = %s, want %s", i, s.Code, want[i])
+		}
+	}
+}
+
+// 冷却窗口以 clock() 为准：假时钟回放历史日期时，按回放当天而不是真实当前时间计算近 N 天。
+func TestApplyCooldownUsesClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	t.Setenv(envHistoryFile, path)
+	t.Setenv(envCooldownDays, "3")
+	t.Setenv(envCooldownMode, "")
+	now := at("2025-01-09", 10, 0)
+	fakeClock(t, now)
+	if err := history.Append(path, now.AddDate(0, 0, -1), "t", []*model.Stock{{Code: "600001", Delivered: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := applyCooldown(context.Background(), []*model.Stock{{Code: "600001"}, {Code: "600002"}})
+	if len(got) != 1 || got[0].Code != "600002" {
+		t.Errorf("applyCooldown = %v, want 仅 600002（600001 前一天已送达）", codesOf(got))
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/mail"
+	"stockMaxWin/internal/trace"
+)
+
+const dailyPoolDayFormat = "2006-01-02"
+
+// dailyPool 调度模式下的当日累计池：跨轮按代码去重合并，记录首次入选时间；日期变化时清空。
+type dailyPool struct {
+	day     string
+	entries map[string]*mail.DailyEntry
+	order   []string // 首次入选顺序
+	sent    bool     // 当日汇总是否已发
+}
+
+// add 合并一轮入选；已在池中的票更新为最新数据并累加轮数，首次入选时间不变。
+func (p *dailyPool) add(res RunResult) {
+	day := res.StartedAt.Format(dailyPoolDayFormat)
+	if day != p.day {
+		p.reset(day)
+	}
+	for _, s := range res.Selected {
+		if s == nil {
+			continue
+		}
+		if e, ok := p.entries[s.Code]; ok {
+			e.Stock = s
+			e.Hits++
+			continue
+		}
+		p.entries[s.Code] = &mail.DailyEntry{Stock: s, FirstSeen: res.StartedAt, Hits: 1}
+		p.order = append(p.order, s.Code)
+	}
+}
+
+func (p *dailyPool) reset(day string) {
+	p.day = day
+	p.entries = make(map[string]*mail.DailyEntry)
+	p.order = nil
+	p.sent = false
+}
+
+func (p *dailyPool) list() []mail.DailyEntry {
+	out := make([]mail.DailyEntry, 0, len(p.order))
+	for _, code := range p.order {
+		out = append(out, *p.entries[code])
+	}
+	return out
+}
+
+// afterClose 判断 t 是否已到收盘执行点（含），收盘 slot 跑完后据此发当日汇总。
+func (s scheduleConfig) afterClose(t time.Time) bool {
+	return t.Hour()*60+t.Minute() >= s.closeHour*60+s.closeMinute
+}
+
+// sendSummaryIfClosed 收盘 slot 之后发一次当日汇总，池为空或已发过则跳过。
+func (p *dailyPool) sendSummaryIfClosed(ctx context.Context, sched scheduleConfig, res RunResult) {
+	if p.sent || !sched.afterClose(res.StartedAt) || res.StartedAt.Format(dailyPoolDayFormat) != p.day {
+		return
+	}
+	p.sent = true
+	entries := p.list()
+	if len(entries) == 0 {
+		trace.Log(ctx, "main: 今日关注池为空，不发汇总")
+		return
+	}
+	mailCfg := buildMailConfig(config.LoadSMTP())
+	if err := mail.SendDailySummary(context.Background(), mailCfg, p.day, entries); err != nil {
+		trace.Log(ctx, "main: 发送今日关注池汇总失败 err=%v", err)
+		return
+	}
+	trace.Log(ctx, "main: 已发今日关注池汇总 %d 只", len(entries))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 当日推送去重：调度模式下同一只票连续多轮入选时避免刷屏。STOCKMAXWIN_PUSH_DEDUPE=skip（默认）不再推送当日已推过的代码，
+// mark 照常推送并标注“持续入选”，off 关闭。已推送集合跨轮保留，交易日变化时清空。
+const (
+	envPushDedupe  = "STOCKMAXWIN_PUSH_DEDUPE"
+	pushDedupeSkip = "skip"
+	pushDedupeMark = "mark"
+	pushDedupeOff  = "off"
+)
+
+// pushDedupeMode 读取去重模式，未配置或无法识别时为 skip。
+func pushDedupeMode() string {
+	switch m := strings.ToLower(strings.TrimSpace(os.Getenv(envPushDedupe))); m {
+	case pushDedupeMark, pushDedupeOff:
+		return m
+	case "0", "false":
+		return pushDedupeOff
+	}
+	return pushDedupeSkip
+}
+
+// pushedSet 当日已推送的代码集合。
+type pushedSet struct {
+	mu    sync.Mutex
+	day   string
+	codes map[string]bool
+}
+
+var pushedToday pushedSet
+
+// resetIfNewDay 日期变化时清空；需持有 mu。
+func (p *pushedSet) resetIfNewDay(now time.Time) {
+	if day := now.Format(dailyPoolDayFormat); day != p.day {
+		p.day = day
+		p.codes = make(map[string]bool)
+	}
+}
+
+// dedupe 按模式处理本轮待推送的股票：skip 剔除当日已推送的，mark 标注 PushedToday 后保留。返回新切片，不改变入参顺序。
+func (p *pushedSet) dedupe(ctx context.Context, now time.Time, stocks []*model.Stock, mode string) []*model.Stock {
+	if mode == pushDedupeOff {
+		return stocks
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetIfNewDay(now)
+	out := make([]*model.Stock, 0, len(stocks))
+	repeated := 0
+	for _, s := range stocks {
+		if !p.codes[s.Code] {
+			out = append(out, s)
+			continue
+		}
+		repeated++
+		if mode == pushDedupeMark {
+			s.PushedToday = true
+			out = append(out, s)
+		}
+	}
+	if repeated > 0 {
+		trace.Log(ctx, "main: 当日已推送过 %d 只（%s），新增 %d 只", repeated, mode, len(stocks)-repeated)
+	}
+	return out
+}
+
+// record 把本轮推送的代码加入当日集合。
+func (p *pushedSet) record(now time.Time, stocks []*model.Stock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetIfNewDay(now)
+	for _, s := range stocks {
+		p.codes[s.Code] = true
+	}
+}
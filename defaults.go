@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/trace"
+)
+
+// runShowDefaultsCommand stockMaxWin --show-defaults：列出趋势动能策略的内置默认阈值，以及叠加 strategy.json 与环境变量后的当前生效值。
+func runShowDefaultsCommand() int {
+	ctx := trace.WithTraceID(context.Background(), trace.NewTraceID())
+	defaults := filter.DefaultThresholds().Describe()
+	current := strategyThresholds(ctx).Describe()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "参数\t默认\t当前\t说明")
+	for i, d := range defaults {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Key, thresholdText(d), thresholdText(current[i]), d.Description)
+	}
+	_ = tw.Flush()
+	fmt.Printf("\n默认策略：%s\n", filter.DefaultThresholds().Summary())
+	fmt.Println("\nfilter.DefaultStrategy 阈值：")
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range filter.DescribeDefaultStrategy() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", d.Key, thresholdText(d), d.Description)
+	}
+	_ = tw.Flush()
+	return 0
+}
+
+func thresholdText(t filter.ThresholdInfo) string {
+	return strconv.FormatFloat(t.Value, 'f', -1, 64) + t.Unit
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"stockMaxWin/internal/export"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 结果导出与列白名单：
+// STOCKMAXWIN_EXPORT_DIR 非空时每轮把入选写成 JSON 文件；STOCKMAXWIN_EXPORT_FIELDS 为导出列白名单（逗号分隔，空为全部列）；
+// STOCKMAXWIN_MAIL_FIELDS 为邮件表格展示列（空为默认列）。列名见 export.AllColumns()。
+// STOCKMAXWIN_CSV_DIR 非空时每轮另写一份固定列的 CSV（见 export.WriteCSV），文件名精确到秒，每轮一份便于留档回测。
+// STOCKMAXWIN_MAIL_CSV=1 时选股邮件另附全部列的 CSV 附件，正文表格不变。
+const (
+	envExportDir         = "STOCKMAXWIN_EXPORT_DIR"
+	envExportFields      = "STOCKMAXWIN_EXPORT_FIELDS"
+	envMailFields        = "STOCKMAXWIN_MAIL_FIELDS"
+	envCSVDir            = "STOCKMAXWIN_CSV_DIR"
+	envMailCSV           = "STOCKMAXWIN_MAIL_CSV"
+	exportFileTimeFormat = "2006-01-02-1504"
+	csvFileTimeFormat    = "2006-01-02-150405"
+	exportDirPerm        = 0o755
+)
+
+func exportSelector() export.Selector {
+	return export.NewSelector(export.ParseFields(os.Getenv(envExportFields)))
+}
+
+func mailFields() []string {
+	return export.ParseFields(os.Getenv(envMailFields))
+}
+
+// mailCSVEnabled 选股邮件是否附带 CSV。
+func mailCSVEnabled() bool {
+	s := os.Getenv(envMailCSV)
+	return s == "1" || s == "true"
+}
+
+// writeExportIfEnabled 开启导出时按列白名单写 selected-时间.json，失败只记日志。
+func writeExportIfEnabled(ctx context.Context, res RunResult) {
+	dir := os.Getenv(envExportDir)
+	if dir == "" {
+		return
+	}
+	name := "selected-" + res.StartedAt.Format(exportFileTimeFormat) + ".json"
+	writeExportFile(ctx, dir, name, "JSON", res.Selected, exportSelector().WriteJSON)
+}
+
+// writeCSVIfEnabled 配置了 STOCKMAXWIN_CSV_DIR 时写 selected-日期-时间.csv，失败只记日志。
+func writeCSVIfEnabled(ctx context.Context, res RunResult) {
+	dir := os.Getenv(envCSVDir)
+	if dir == "" {
+		return
+	}
+	name := "selected-" + res.StartedAt.Format(csvFileTimeFormat) + ".csv"
+	writeExportFile(ctx, dir, name, "CSV", res.Selected, export.WriteCSV)
+}
+
+func writeExportFile(ctx context.Context, dir, name, kind string, stocks []*model.Stock, write func(io.Writer, []*model.Stock) error) {
+	if err := os.MkdirAll(dir, exportDirPerm); err != nil {
+		trace.Log(ctx, "main: 导出目录创建失败 err=%v", err)
+		return
+	}
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		trace.Log(ctx, "main: 导出文件创建失败 err=%v", err)
+		return
+	}
+	if err := write(f, stocks); err != nil {
+		_ = f.Close()
+		trace.Log(ctx, "main: 导出 %s 失败 err=%v", kind, err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		trace.Log(ctx, "main: 导出 %s 失败 err=%v", kind, err)
+		return
+	}
+	trace.Log(ctx, "main: 已导出 %d 只 -> %s", len(stocks), path)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/mail"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// 大盘择时：STOCKMAXWIN_MARKET_GATE 为逗号分隔的规则，全部满足才开闸，如 "ma20,change_min:-1.5"；
+// ma20 参考指数现价站上 20 日均线，change_min:x 参考指数当日涨跌幅不低于 x%。
+// STOCKMAXWIN_MARKET_GATE_INDEX 参考指数代码，默认上证指数 000001（可选 399001 深证成指、399006 创业板指）。
+// 未配置时始终开闸，与原先行为一致。
+const (
+	envMarketGate      = "STOCKMAXWIN_MARKET_GATE"
+	envMarketGateIndex = "STOCKMAXWIN_MARKET_GATE_INDEX"
+	defaultGateIndex   = "000001"
+	gateRuleMA20       = "ma20"
+	gateRuleChangeMin  = "change_min"
+	gateMAPeriod       = 20
+)
+
+// MarketGate 大盘择时开关：输入大盘指数，返回是否允许本轮选股；关闸时 reason 说明原因。
+type MarketGate func(ctx context.Context, indices []model.IndexQuote) (open bool, reason string)
+
+// marketGate 当前择时规则，nil 表示始终开闸（不额外拉指数）；启动时由 buildMarketGate 构建，嵌入时可直接替换。
+var marketGate MarketGate
+
+// gateNotice 关闸通知按自然日去重，调度模式下同一天只发一次“空仓观望”。
+var gateNotice struct {
+	mu  sync.Mutex
+	day string
+}
+
+// buildMarketGate 按环境变量构建择时规则，未配置或规则全部无效时返回 nil。
+func buildMarketGate() MarketGate {
+	spec := strings.TrimSpace(os.Getenv(envMarketGate))
+	if spec == "" {
+		return nil
+	}
+	index := strings.TrimSpace(os.Getenv(envMarketGateIndex))
+	if index == "" {
+		index = defaultGateIndex
+	}
+	var gates []MarketGate
+	for _, rule := range strings.Split(spec, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), ":")
+		switch strings.ToLower(name) {
+		case "":
+			continue
+		case gateRuleMA20:
+			gates = append(gates, indexAboveMA20(index))
+		case gateRuleChangeMin:
+			v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+			if err != nil {
+				trace.Log(context.Background(), "main: 择时规则 %q 阈值无效，忽略 err=%v", rule, err)
+				continue
+			}
+			gates = append(gates, indexChangeMin(index, v))
+		default:
+			trace.Log(context.Background(), "main: 未知择时规则 %q，忽略", rule)
+		}
+	}
+	if len(gates) == 0 {
+		return nil
+	}
+	return allGates(gates...)
+}
+
+// allGates 依次判断，任一关闸即关闸并返回其原因。
+func allGates(gates ...MarketGate) MarketGate {
+	return func(ctx context.Context, indices []model.IndexQuote) (bool, string) {
+		for _, g := range gates {
+			if open, reason := g(ctx, indices); !open {
+				return false, reason
+			}
+		}
+		return true, ""
+	}
+}
+
+// findIndex 在大盘数据中按代码查找参考指数。
+func findIndex(indices []model.IndexQuote, code string) (model.IndexQuote, bool) {
+	for _, q := range indices {
+		if q.Code == code {
+			return q, true
+		}
+	}
+	return model.IndexQuote{}, false
+}
+
+// indexChangeMin 参考指数当日涨跌幅低于 minPct 时关闸；找不到该指数时放行。
+func indexChangeMin(code string, minPct float64) MarketGate {
+	return func(ctx context.Context, indices []model.IndexQuote) (bool, string) {
+		q, ok := findIndex(indices, code)
+		if !ok {
+			trace.Log(ctx, "main: 择时未找到指数 %s，涨跌幅规则放行", code)
+			return true, ""
+		}
+		if q.ChangePct < minPct {
+			return false, fmt.Sprintf("%s 涨跌幅 %.2f%% 低于 %.2f%%", q.Name, q.ChangePct, minPct)
+		}
+		return true, ""
+	}
+}
+
+// indexAboveMA20 参考指数现价跌破 20 日均线时关闸；指数 K 线拉取失败或不足 20 根时放行。
+func indexAboveMA20(code string) MarketGate {
+	return func(ctx context.Context, indices []model.IndexQuote) (bool, string) {
+		q, ok := findIndex(indices, code)
+		if !ok {
+			trace.Log(ctx, "main: 择时未找到指数 %s，MA20 规则放行", code)
+			return true, ""
+		}
+		klines, err := apiClient.GetIndexKlines(ctx, code, worker.KlineCountFor(worker.IndicatorMA20))
+		if err != nil || len(klines) < gateMAPeriod {
+			trace.Log(ctx, "main: 择时拉取指数 %s 日 K 失败或不足 20 根，MA20 规则放行 n=%d err=%v", code, len(klines), err)
+			return true, ""
+		}
+		ma20 := worker.MA20(klines)
+		if q.Price < ma20 {
+			return false, fmt.Sprintf("%s %.2f 跌破 MA20 %.2f", q.Name, q.Price, ma20)
+		}
+		return true, ""
+	}
+}
+
+// checkMarketGate 配置了择时时拉大盘并判断，返回是否开闸、关闸原因与大盘数据；拉取失败按开闸处理，避免接口抖动误停选股。
+func checkMarketGate(ctx context.Context) (open bool, reason string, indices []model.IndexQuote) {
+	gate := marketGate
+	if gate == nil {
+		return true, "", nil
+	}
+	indices, err := apiClient.GetIndexQuotes(ctx)
+	if err != nil {
+		trace.Log(ctx, "main: 择时获取大盘数据失败，按开闸处理 err=%v", err)
+		return true, "", nil
+	}
+	open, reason = gate(ctx, indices)
+	return open, reason, indices
+}
+
+// sendMarketGateNotice 发“空仓观望”邮件，同一天只发一次，失败只记日志（当天下一轮关闸时重试）。
+func sendMarketGateNotice(ctx context.Context, reason string, indices []model.IndexQuote) {
+	day := clock().Format(dailyPoolDayFormat)
+	gateNotice.mu.Lock()
+	defer gateNotice.mu.Unlock()
+	if gateNotice.day == day {
+		trace.Log(ctx, "main: 今日已发空仓观望通知，不再重复")
+		return
+	}
+	if err := mail.SendMarketGateClosed(ctx, buildMailConfig(config.LoadSMTP()), reason, indices); err != nil {
+		trace.Log(ctx, "main: 发送空仓观望通知失败 err=%v", err)
+		return
+	}
+	gateNotice.day = day
+}
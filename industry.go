@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// STOCKMAXWIN_INDUSTRY_TOP=n 仅保留所属行业当日涨幅排名前 n 的股票（叠加到策略上，见 strategyFilter）
+const envIndustryTop = "STOCKMAXWIN_INDUSTRY_TOP"
+
+func industryTopN() int {
+	if s := os.Getenv(envIndustryTop); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// annotateIndustryHeat 拉行业板块涨幅榜，给每只候选标注所属行业当日涨幅与排名；拉取失败只记日志。
+func annotateIndustryHeat(ctx context.Context, quotes []model.StockQuote) {
+	boards, err := apiClient.GetIndustryBoards(ctx)
+	if err != nil {
+		trace.Log(ctx, "main: GetIndustryBoards err=%v，跳过行业热度标注", err)
+		return
+	}
+	byName := make(map[string]model.IndustryBoard, len(boards))
+	for _, b := range boards {
+		byName[b.Name] = b
+	}
+	matched := 0
+	for i := range quotes {
+		b, ok := byName[quotes[i].Industry]
+		if !ok {
+			continue
+		}
+		quotes[i].IndustryChangePct = b.ChangePct
+		quotes[i].IndustryRank = b.Rank
+		matched++
+	}
+	trace.Log(ctx, "main: 行业板块 %d 个，候选标注行业热度 %d/%d 只", len(boards), matched, len(quotes))
+}
+
+// annotateConceptHeat 拉概念板块涨幅榜，为每只候选标注所属概念中当日最强的一个；拉取失败或无概念数据时留空。
+func annotateConceptHeat(ctx context.Context, quotes []model.StockQuote) {
+	boards, err := apiClient.GetConceptBoards(ctx)
+	if err != nil {
+		trace.Log(ctx, "main: GetConceptBoards err=%v，跳过概念标注", err)
+		return
+	}
+	byName := make(map[string]model.ConceptBoard, len(boards))
+	for _, b := range boards {
+		byName[b.Name] = b
+	}
+	matched := 0
+	for i := range quotes {
+		var best *model.ConceptBoard
+		for _, name := range quotes[i].Concepts {
+			b, ok := byName[name]
+			if !ok {
+				continue
+			}
+			if best == nil || b.ChangePct > best.ChangePct {
+				best = &b
+			}
+		}
+		if best == nil {
+			continue
+		}
+		quotes[i].TopConcept = best.Name
+		quotes[i].TopConceptChangePct = best.ChangePct
+		matched++
+	}
+	trace.Log(ctx, "main: 概念板块 %d 个，候选标注最强概念 %d/%d 只", len(boards), matched, len(quotes))
+}
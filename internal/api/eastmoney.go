@@ -7,9 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
+	"math"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/tidwall/gjson"
 	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/proxy"
 	"stockMaxWin/internal/trace"
 )
 
@@ -25,6 +27,8 @@ const (
 	envAPIDelayMS       = "STOCKMAXWIN_API_DELAY_MS"
 	envAPIJitterMS      = "STOCKMAXWIN_API_JITTER_MS"
 	envAPIMaxConcurrent = "STOCKMAXWIN_API_MAX_CONCURRENT"
+	envAPISplitMarket   = "STOCKMAXWIN_API_SPLIT_MARKET"
+	envAPIParallelPages = "STOCKMAXWIN_API_PARALLEL_PAGES"
 )
 
 // 东方财富接口地址
@@ -33,20 +37,57 @@ const (
 	EastMoneyKLineURL  = "https://push2his.eastmoney.com/api/qt/stock/kline/get"
 	EastMoneyIndexURL  = "https://push2.eastmoney.com/api/qt/ulist.np/get"
 	indexSecIDs        = "1.000001,0.399001,0.399006" // 上证指数、深证成指、创业板指
-	indexFields        = "f12,f14,f2,f3"              // 代码、名称、现价、涨跌幅
+	indexFields        = "f12,f14,f2,f3,f17,f18"      // 代码、名称、现价、涨跌幅、今开、昨收
 )
 
-// 列表接口请求字段：f2 现价 f3 涨跌幅(%) f6 成交量 f8 换手 f10 量比 f12 代码 f14 名称 f23 成交额 f20 总市值 f9 市盈率
-const listFieldsMainBoard = "f2,f3,f6,f8,f10,f12,f14,f23,f20,f9"
+// 列表接口请求字段：f2 现价 f3 涨跌幅(%) f5 成交量(手) f6 成交额 f8 换手 f10 量比 f12 代码 f14 名称 f20 总市值 f9 市盈率 f62 主力净流入
+// 基本面：f23 市净率 f37 ROE(%) f41 营收同比(%) f46 净利润同比(%)；f100 所属行业 f103 所属概念（逗号分隔）
+const listFieldsMainBoard = "f2,f3,f5,f6,f8,f10,f12,f14,f20,f9,f62,f23,f37,f41,f46,f100,f103"
 
 // 指数接口 ulist 的 f3 为“百分比×100”，如 -0.25% 返回 -25，需除以 100 后使用
 const indexChangePctDivisor = 100
 
+// 主板市场参数：合并请求用 fsMainBoard，拆分请求按 mainBoardMarkets 逐个市场拉取
+const fsMainBoard = "m:1+t:2,m:0+t:2"
+
+var mainBoardMarkets = []struct {
+	name string
+	fs   string
+}{
+	{name: "沪市主板", fs: "m:1+t:2"},
+	{name: "深市主板", fs: "m:0+t:2"},
+}
+
+// 非主板的板块 fs：创业板、科创板、北交所（主板走 GetMainBoardQuotes 的拆分请求）
+var boardFS = map[model.Board]string{
+	model.BoardChiNext: "m:0+t:80",
+	model.BoardSTAR:    "m:1+t:23",
+	model.BoardBSE:     "m:0+t:81+s:2048",
+}
+
+// 行业板块列表：fs 行业板块，按 f3 涨跌幅降序；字段 f12 板块代码 f14 名称 f3 涨跌幅
+const (
+	fsIndustryBoards     = "m:90+t:2"
+	industryBoardsFields = "f12,f14,f3"
+)
+
+// 概念板块列表：fs 概念板块，字段同行业板块；板块数较多需翻页。当日涨幅在缓存期内复用，避免每轮重复拉取
+const (
+	fsConceptBoards = "m:90+t:3"
+	conceptCacheTTL = 5 * time.Minute
+)
+
 // 全市场列表字段：f12 代码 f14 名称
 const listFieldsBrief = "f12,f14"
 
-// 分页
-const listPageSize = 500
+// 全市场 fs：深市 A 股（主板/创业板）、沪市 A 股（主板/科创板），GetAllStocks 与 GetAllQuotes 共用
+const fsAllMarket = "m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23"
+
+// 分页：每页条数与翻页上限（防 total 异常时死循环）
+const (
+	listPageSize = 500
+	maxListPages = 40
+)
 
 // 请求超时与重试
 const (
@@ -74,72 +115,136 @@ const (
 )
 
 var (
-	requestGap       = defaultRequestGap
-	requestJitter    = defaultRequestJitter
-	maxConcurrent    = defaultMaxConcurrent
-	concurrentSem    chan struct{}
-	lastReqTime      time.Time
-	lastReqMu        sync.Mutex
-	requestGapMu     sync.Mutex
+	// splitMarketRequests 行情列表按市场拆分请求，STOCKMAXWIN_API_SPLIT_MARKET=0 时退回一次请求沪深
+	splitMarketRequests = true
+	// parallelPages 行情列表首页拿到 total 后并发拉剩余页，STOCKMAXWIN_API_PARALLEL_PAGES=0 时退回串行翻页
+	parallelPages = true
 )
 
+// init 按环境变量设置包级默认节流（defaultThrottle），未配置的项保持内置默认值。
 func init() {
+	l := Limits{JitterMS: -1}
 	if s := os.Getenv(envAPIDelayMS); s != "" {
 		if ms, err := strconv.Atoi(s); err == nil && ms > 0 {
-			requestGap = time.Duration(ms) * time.Millisecond
+			l.RequestGap = time.Duration(ms) * time.Millisecond
 		}
 	}
 	if s := os.Getenv(envAPIJitterMS); s != "" {
 		if ms, err := strconv.Atoi(s); err == nil && ms >= 0 {
-			requestJitter = ms
+			l.JitterMS = ms
 		}
 	}
-	n := defaultMaxConcurrent
 	if s := os.Getenv(envAPIMaxConcurrent); s != "" {
 		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			n = v
-			if n > maxConcurrentCap {
-				n = maxConcurrentCap
+			l.MaxConcurrent = v
+		}
+	}
+	if s := os.Getenv(envAPISplitMarket); s == "0" || s == "false" {
+		splitMarketRequests = false
+	}
+	if s := os.Getenv(envAPIParallelPages); s == "0" || s == "false" {
+		parallelPages = false
+	}
+	if s := os.Getenv(envAPIRPS); s != "" {
+		if rps, burst, err := ParseRPS(s); err == nil {
+			if rps == 0 {
+				rps = RPSFixedGap
 			}
-			maxConcurrent = n
+			l.RPS, l.Burst = rps, burst
 		}
 	}
-	concurrentSem = make(chan struct{}, maxConcurrent)
+	defaultThrottle.set(l)
+}
+
+// Limits 请求节流参数：令牌桶速率与突发量，或固定间隔、抖动(毫秒)；以及同时进行中的请求上限。
+// RPS>0 时用令牌桶（间隔与抖动不生效），RPS 为 RPSFixedGap 时用固定间隔 + 抖动。
+type Limits struct {
+	RPS           float64
+	Burst         int
+	RequestGap    time.Duration
+	JitterMS      int
+	MaxConcurrent int
+}
+
+// CurrentLimits 返回包级默认节流参数（NewClient 创建的客户端共享）。
+func CurrentLimits() Limits {
+	return defaultThrottle.limits()
+}
+
+// SetLimits 运行中调整包级默认节流参数：RPS==0、RequestGap<=0、JitterMS<0、MaxConcurrent<=0 的项保持不变。
+// 并发上限变化时替换信号量，已在进行中的请求仍归还到原信号量，不受影响。经 NewClientWithOptions 创建的客户端不受影响。
+func SetLimits(l Limits) Limits {
+	return defaultThrottle.set(l)
 }
 
 type Client struct {
 	HTTPClient *http.Client
+
+	// 接口地址，为空时用默认的东方财富地址；通常经 SetEndpoints 设置
+	ListURL  string
+	KLineURL string
+	IndexURL string
+
+	// KlineCacheDir 非空时日 K 走磁盘增量缓存（见 klinecache.go），通常经 SetKlineCacheDir 设置
+	KlineCacheDir string
+
+	klineMu   sync.Mutex
+	klineTTL  time.Duration
+	klineMemo map[string]klineMemoEntry
+
+	profileMu sync.Mutex
+	profiles  map[string]model.StockProfile
+
+	conceptMu     sync.Mutex
+	conceptBoards []model.ConceptBoard
+	conceptAt     time.Time
+
+	// throttle 该客户端的节流与并发上限，nil 时用包级 defaultThrottle
+	throttle *throttle
 }
 
+// NewClient 构造客户端：Transport 按系统 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 走代理并调大连接复用，
+// 需按域名分流代理时由调用方替换 HTTPClient.Transport（见 proxy.Rules）。节流使用包级默认（环境变量与 SetLimits）。
 func NewClient() *Client {
-	return &Client{HTTPClient: &http.Client{Timeout: defaultHTTPTimeout}}
+	return &Client{HTTPClient: &http.Client{Timeout: defaultHTTPTimeout, Transport: proxy.Rules{}.Transport()}}
 }
 
-func paceRequest(ctx context.Context) {
-	requestGapMu.Lock()
-	gap := requestGap
-	jitter := requestJitter
-	requestGapMu.Unlock()
-	if gap <= 0 && jitter <= 0 {
-		return
-	}
-	lastReqMu.Lock()
-	elapsed := time.Since(lastReqTime)
-	lastReqMu.Unlock()
-	d := gap - elapsed
-	if jitter > 0 {
-		d += time.Duration(rand.Intn(jitter+1)) * time.Millisecond
+// ClientOptions NewClientWithOptions 的参数。
+type ClientOptions struct {
+	// Limits 该客户端独立的节流参数，在内置默认值（令牌桶 5 rps、并发 4）上覆盖，
+	// 取值语义同 SetLimits：RPS==0、RequestGap<=0、JitterMS<0、MaxConcurrent<=0 的项用默认值
+	Limits Limits
+	// HTTPClient 为 nil 时同 NewClient
+	HTTPClient *http.Client
+}
+
+// NewClientWithOptions 构造拥有独立节流与并发信号量的客户端，不受环境变量与 SetLimits 影响，
+// 用于给不同用途的客户端配不同限流，或在单测中构造互不干扰的客户端。
+func NewClientWithOptions(opts ClientOptions) *Client {
+	c := NewClient()
+	if opts.HTTPClient != nil {
+		c.HTTPClient = opts.HTTPClient
 	}
-	if d > 0 {
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(d):
-		}
+	c.throttle = newThrottle()
+	c.throttle.set(opts.Limits)
+	return c
+}
+
+// Limits 返回该客户端当前生效的节流参数。
+func (c *Client) Limits() Limits {
+	return c.limiter().limits()
+}
+
+// SetLimits 调整该客户端的节流参数，语义同包级 SetLimits；未经 NewClientWithOptions 创建的客户端调整的是包级默认。
+func (c *Client) SetLimits(l Limits) Limits {
+	return c.limiter().set(l)
+}
+
+func (c *Client) limiter() *throttle {
+	if c.throttle != nil {
+		return c.throttle
 	}
-	lastReqMu.Lock()
-	lastReqTime = time.Now()
-	lastReqMu.Unlock()
+	return defaultThrottle
 }
 
 func (c *Client) doWithRetry(ctx context.Context, method, url string) (*http.Response, error) {
@@ -167,15 +272,19 @@ func (c *Client) doWithRetry(ctx context.Context, method, url string) (*http.Res
 			case <-time.After(backoff):
 			}
 		}
-		paceRequest(ctx)
+		th := c.limiter()
+		if err := th.wait(ctx); err != nil {
+			return nil, err
+		}
+		sem := th.currentSem()
 		select {
-		case concurrentSem <- struct{}{}:
+		case sem <- struct{}{}:
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
 		req, err := http.NewRequestWithContext(ctx, method, url, nil)
 		if err != nil {
-			<-concurrentSem
+			<-sem
 			lastErr = err
 			continue
 		}
@@ -183,19 +292,22 @@ func (c *Client) doWithRetry(ctx context.Context, method, url string) (*http.Res
 		req.Header.Set("Referer", referer)
 		req.Header.Set("Accept", "application/json, text/plain, */*")
 		req.Header.Set("Accept-Language", acceptLanguage)
-		trace.Log(ctx, "api: req %s %s", method, url)
+		trace.Debug(ctx, "api: req %s %s", method, url)
 		resp, err := client.Do(req)
 		if err != nil {
-			<-concurrentSem
+			<-sem
 			lastErr = err
 			continue
 		}
 		if resp.StatusCode != http.StatusOK {
 			lastStatus = resp.StatusCode
+			if resp.StatusCode == httpStatusTooMany {
+				th.onTooMany(ctx)
+			}
 			body, _ := io.ReadAll(resp.Body)
 			_ = resp.Body.Close()
-			<-concurrentSem
-			trace.Log(ctx, "api: resp status=%d len=%d body=%s", resp.StatusCode, len(body), truncateForLog(body))
+			<-sem
+			trace.Warn(ctx, "api: resp status=%d len=%d body=%s", resp.StatusCode, len(body), truncateForLog(body))
 			lastErr = fmt.Errorf("http %d", resp.StatusCode)
 			continue
 		}
@@ -203,16 +315,17 @@ func (c *Client) doWithRetry(ctx context.Context, method, url string) (*http.Res
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			_ = resp.Body.Close()
-			<-concurrentSem
+			<-sem
 			lastErr = err
 			continue
 		}
 		_ = resp.Body.Close()
-		trace.Log(ctx, "api: resp status=%d len=%d body=%s", resp.StatusCode, len(body), truncateForLog(body))
-		resp.Body = &releaseOnClose{Reader: bytes.NewReader(body), release: func() { <-concurrentSem }}
+		th.onSuccess(ctx)
+		trace.Debug(ctx, "api: resp status=%d len=%d body=%s", resp.StatusCode, len(body), truncateForLog(body))
+		resp.Body = &releaseOnClose{Reader: bytes.NewReader(body), release: func() { <-sem }}
 		return resp, nil
 	}
-	trace.Log(ctx, "api: doWithRetry fail url=%s err=%v", url, lastErr)
+	trace.Warn(ctx, "api: doWithRetry fail url=%s err=%v", url, lastErr)
 	return nil, lastErr
 }
 
@@ -240,63 +353,218 @@ func truncateForLog(b []byte) string {
 func (c *Client) GetAllStocks(ctx context.Context) ([]model.StockBrief, error) {
 	var all []model.StockBrief
 	page := 1
+	total := 0
 	for {
-		url := fmt.Sprintf("%s?pn=%d&pz=%d&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=%s",
-			EastMoneyListURL, page, listPageSize, listFieldsBrief)
+		url := fmt.Sprintf("%s?pn=%d&pz=%d&fs=%s&fields=%s",
+			c.listURL(), page, listPageSize, fsAllMarket, listFieldsBrief)
 		resp, err := c.doWithRetry(ctx, http.MethodGet, url)
 		if err != nil {
 			return nil, err
 		}
-		total, count, err := decodeStockListStream(resp.Body, &all)
+		pageTotal, count, err := decodeStockListStream(resp.Body, &all)
 		_ = resp.Body.Close()
 		if err != nil && err != io.EOF {
 			return nil, err
 		}
+		if pageTotal > 0 {
+			total = pageTotal
+		}
 		if count == 0 {
 			break
 		}
 		if total <= len(all) || count < listPageSize {
 			break
 		}
+		if page >= maxListPages {
+			trace.Log(ctx, "api: GetAllStocks 翻页达上限 %d 仍未取完 total=%d got=%d，停止翻页", maxListPages, total, len(all))
+			break
+		}
 		page++
 	}
+	checkListTotal(ctx, "GetAllStocks", total, len(all))
 	return all, nil
 }
 
+// checkListTotal 拉完后校验实际条数与接口 total 是否一致；total 偶发不准时记录告警，避免静默漏股。
+func checkListTotal(ctx context.Context, name string, total, got int) {
+	if total <= 0 || total == got {
+		return
+	}
+	diff := total - got
+	if diff < 0 {
+		diff = -diff
+	}
+	trace.Warn(ctx, "api: %s 条数与 total 不一致 total=%d got=%d diff=%d，可能漏股或接口 total 不准", name, total, got, diff)
+}
+
+// GetMainBoardQuotes 拉取沪深主板行情。默认按市场拆分请求（沪、深各一次）再合并，
+// 单个市场失败只记日志不影响另一市场；全部失败才返回错误。
 func (c *Client) GetMainBoardQuotes(ctx context.Context) ([]model.StockQuote, error) {
+	trace.Log(ctx, "api: GetMainBoardQuotes start split=%v", splitMarketRequests)
+	if !splitMarketRequests {
+		list, err := c.getQuotesByFS(ctx, fsMainBoard)
+		if err != nil {
+			return nil, err
+		}
+		logMainBoardDone(ctx, list)
+		return list, nil
+	}
+	var list []model.StockQuote
+	var lastErr error
+	failed := 0
+	for _, m := range mainBoardMarkets {
+		part, err := c.getQuotesByFS(ctx, m.fs)
+		if err != nil {
+			failed++
+			lastErr = err
+			trace.Log(ctx, "api: GetMainBoardQuotes %s 失败，跳过该市场 err=%v", m.name, err)
+			continue
+		}
+		trace.Log(ctx, "api: GetMainBoardQuotes %s len=%d", m.name, len(part))
+		list = append(list, part...)
+	}
+	if failed == len(mainBoardMarkets) {
+		return nil, lastErr
+	}
+	logMainBoardDone(ctx, list)
+	return list, nil
+}
+
+// GetBoardQuotes 拉取指定板块行情，字段同 GetMainBoardQuotes；主板沿用 GetMainBoardQuotes 的按市场拆分逻辑。
+func (c *Client) GetBoardQuotes(ctx context.Context, board model.Board) ([]model.StockQuote, error) {
+	if board == model.BoardMain {
+		return c.GetMainBoardQuotes(ctx)
+	}
+	fs, ok := boardFS[board]
+	if !ok {
+		return nil, fmt.Errorf("%w: board=%q", ErrInvalidArgument, board)
+	}
+	list, err := c.getQuotesByFS(ctx, fs)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(ctx, "api: GetBoardQuotes %s done len=%d", board, len(list))
+	return list, nil
+}
+
+// GetAllQuotes 拉取全市场（与 GetAllStocks 同一范围）行情，字段同 GetMainBoardQuotes，
+// 板块限定交由初选（filter.PreFilterOptions.Boards）完成。
+func (c *Client) GetAllQuotes(ctx context.Context) ([]model.StockQuote, error) {
+	trace.Log(ctx, "api: GetAllQuotes start")
+	list, err := c.getQuotesByFS(ctx, fsAllMarket)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(ctx, "api: GetAllQuotes done len=%d", len(list))
+	return list, nil
+}
+
+func logMainBoardDone(ctx context.Context, list []model.StockQuote) {
+	trace.Log(ctx, "api: GetMainBoardQuotes done len=%d", len(list))
+	if len(list) == 0 {
+		trace.Log(ctx, "api: 主板结果为空，可浏览器打开上述 url 或检查 data.diff 是否被跳过")
+	}
+}
+
+// getQuotesByFS 按 fs 市场参数分页拉取行情列表，翻页判断只针对本次 fs 的 total。
+// 默认先拉首页拿 total，再并发拉剩余页（在途请求数受客户端并发上限约束）并按页序合并；parallelPages 关闭时串行翻页。
+func (c *Client) getQuotesByFS(ctx context.Context, fs string) ([]model.StockQuote, error) {
+	if !parallelPages {
+		return c.getQuotesByFSSerial(ctx, fs)
+	}
+	list, total, err := c.fetchQuotePage(ctx, fs, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := (total + listPageSize - 1) / listPageSize
+	if pages > maxListPages {
+		trace.Log(ctx, "api: fs=%s 总页数 %d 超过上限 %d，只拉前 %d 页 total=%d", fs, pages, maxListPages, maxListPages, total)
+		pages = maxListPages
+	}
+	if len(list) < listPageSize || pages <= 1 {
+		checkListTotal(ctx, "getQuotesByFS fs="+fs, total, len(list))
+		return list, nil
+	}
+	rest := make([][]model.StockQuote, pages-1)
+	errs := make([]error, pages-1)
+	var wg sync.WaitGroup
+	for i := range rest {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rest[i], _, errs[i] = c.fetchQuotePage(ctx, fs, i+2)
+		}(i)
+	}
+	wg.Wait()
+	var failed []int
+	for i, part := range rest {
+		if errs[i] != nil {
+			failed = append(failed, i+2)
+			trace.Log(ctx, "api: fs=%s 第 %d 页失败 err=%v", fs, i+2, errs[i])
+			continue
+		}
+		list = append(list, part...)
+	}
+	if len(failed) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		trace.Warn(ctx, "api: fs=%s 共 %d 页，第 %v 页拉取失败，返回已成功的 %d 条", fs, pages, failed, len(list))
+	}
+	checkListTotal(ctx, "getQuotesByFS fs="+fs, total, len(list))
+	return list, nil
+}
+
+// getQuotesByFSSerial 串行翻页版本，按 total 与本页条数判断是否继续，保留用于对比与兜底。
+func (c *Client) getQuotesByFSSerial(ctx context.Context, fs string) ([]model.StockQuote, error) {
 	var list []model.StockQuote
 	page := 1
-	trace.Log(ctx, "api: GetMainBoardQuotes start")
+	total := 0
 	for {
-		url := fmt.Sprintf("%s?pn=%d&pz=%d&fs=m:1+t:2,m:0+t:2&fields=%s",
-			EastMoneyListURL, page, listPageSize, listFieldsMainBoard)
-		if page == 1 {
-			trace.Log(ctx, "api: GetMainBoardQuotes url=%s", url)
-		}
-		resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+		part, pageTotal, err := c.fetchQuotePage(ctx, fs, page)
 		if err != nil {
 			return nil, err
 		}
-		total, count, err := decodeQuoteListStream(ctx, resp.Body, &list)
-		_ = resp.Body.Close()
-		if err != nil && err != io.EOF {
-			return nil, err
+		list = append(list, part...)
+		if pageTotal > 0 {
+			total = pageTotal
 		}
-		if count == 0 {
+		if len(part) == 0 {
+			break
+		}
+		if total <= len(list) || len(part) < listPageSize {
 			break
 		}
-		if total <= len(list) || count < listPageSize {
+		if page >= maxListPages {
+			trace.Log(ctx, "api: fs=%s 翻页达上限 %d 仍未取完 total=%d got=%d，停止翻页", fs, maxListPages, total, len(list))
 			break
 		}
 		page++
 	}
-	trace.Log(ctx, "api: GetMainBoardQuotes done len=%d", len(list))
-	if len(list) == 0 {
-		trace.Log(ctx, "api: 主板结果为空，可浏览器打开上述 url 或检查 data.diff 是否被跳过")
-	}
+	checkListTotal(ctx, "getQuotesByFS fs="+fs, total, len(list))
 	return list, nil
 }
 
+// fetchQuotePage 拉取行情列表第 page 页，返回本页行情与接口 total。
+func (c *Client) fetchQuotePage(ctx context.Context, fs string, page int) ([]model.StockQuote, int, error) {
+	url := fmt.Sprintf("%s?pn=%d&pz=%d&fs=%s&fields=%s",
+		c.listURL(), page, listPageSize, fs, listFieldsMainBoard)
+	if page == 1 {
+		trace.Log(ctx, "api: getQuotesByFS url=%s", url)
+	}
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	var list []model.StockQuote
+	total, _, err := decodeQuoteListStream(ctx, resp.Body, &list)
+	if err != nil && err != io.EOF {
+		return nil, total, err
+	}
+	return list, total, nil
+}
+
 // decodeQuoteListStream 解析列表接口 JSON：根对象下 data.total、data.diff（数组或对象 "0","1",...）
 func decodeQuoteListStream(ctx context.Context, r io.Reader, list *[]model.StockQuote) (total int, count int, err error) {
 	dec := json.NewDecoder(r)
@@ -393,22 +661,29 @@ func decodeQuoteListStream(ctx context.Context, r io.Reader, list *[]model.Stock
 	return total, count, nil
 }
 
-// quoteItemFields 对应东方财富 data.diff 单条：f2 现价 f3 涨跌幅 f6 成交量 f8 换手率 f10 量比 f12 代码 f14 名称 f23 成交额 f20 总市值 f9 市盈率
+// quoteItemFields 对应东方财富 data.diff 单条：f2 现价 f3 涨跌幅 f5 成交量 f6 成交额 f8 换手率 f10 量比 f12 代码 f14 名称 f20 总市值 f9 市盈率
+// f23 市净率 f37 ROE f41 营收同比 f46 净利润同比；基本面字段缺失时接口返回 "-"，按 0（缺失）处理
 func decodeQuoteItem(dec *json.Decoder, list *[]model.StockQuote) error {
 	var item struct {
 		F2   json.Number `json:"f2"`
 		F3   json.Number `json:"f3"`
+		F5   json.Number `json:"f5"`
 		F6   json.Number `json:"f6"`
 		F8   json.Number `json:"f8"`
 		F10  json.Number `json:"f10"`
 		F12  string      `json:"f12"`
 		F14  string      `json:"f14"`
-		F23  json.Number `json:"f23"`
 		F20  json.Number `json:"f20"`
 		F9   json.Number `json:"f9"`
 		F62  json.Number `json:"f62"`
 		F184 json.Number `json:"f184"`
 		F66  json.Number `json:"f66"`
+		F23  optionalFloat `json:"f23"`
+		F37  optionalFloat `json:"f37"`
+		F41  optionalFloat `json:"f41"`
+		F46  optionalFloat `json:"f46"`
+		F100 string        `json:"f100"`
+		F103 string        `json:"f103"`
 	}
 	if err := dec.Decode(&item); err != nil {
 		return err
@@ -418,10 +693,10 @@ func decodeQuoteItem(dec *json.Decoder, list *[]model.StockQuote) error {
 	}
 	price, _ := item.F2.Float64()
 	changePct, _ := item.F3.Float64()
-	vol, _ := item.F6.Int64()
+	vol, _ := item.F5.Int64()
 	turnoverRate, _ := item.F8.Float64()
 	volumeRatio, _ := item.F10.Float64()
-	amount, _ := item.F23.Float64()
+	amount, _ := item.F6.Float64()
 	if amount <= 0 && vol > 0 && price > 0 {
 		amount = float64(vol) * 100 * price
 	}
@@ -446,10 +721,45 @@ func decodeQuoteItem(dec *json.Decoder, list *[]model.StockQuote) error {
 		NetInflow:        netInflow,
 		MainForceInflow:  mainIn,
 		MainForceOutflow: mainOut,
+		PB:               float64(item.F23),
+		ROE:              float64(item.F37),
+		RevenueGrowth:    float64(item.F41),
+		ProfitGrowth:     float64(item.F46),
+		Industry:         strings.TrimSpace(item.F100),
+		Concepts:         splitConcepts(item.F103),
 	})
 	return nil
 }
 
+// splitConcepts 解析 f103 逗号分隔的概念名，缺失（"-" 或空）返回 nil。
+func splitConcepts(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "-" {
+		return nil
+	}
+	var out []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// optionalFloat 兼容数字与字符串（如缺失时的 "-"），无法解析时为 0。
+type optionalFloat float64
+
+func (f *optionalFloat) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(strings.TrimSpace(string(b)), `"`)
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		*f = 0
+		return nil
+	}
+	*f = optionalFloat(v)
+	return nil
+}
+
 func decodeStockListStream(r io.Reader, list *[]model.StockBrief) (total int, count int, err error) {
 	dec := json.NewDecoder(r)
 	if t, err := dec.Token(); err != nil {
@@ -529,33 +839,210 @@ func decodeStockListStream(r io.Reader, list *[]model.StockBrief) (total int, co
 	return total, count, nil
 }
 
-// GetHisKlines 拉取 A 股前复权历史 K 线，count 为条数；使用东方财富 API，fqt=1 前复权，5 秒超时。
+// KLinePeriod K 线周期，取值即东方财富 kline/get 的 klt 参数。
+type KLinePeriod int
+
+const (
+	KLineDaily   KLinePeriod = 101
+	KLineWeekly  KLinePeriod = 102
+	KLineMonthly KLinePeriod = 103
+	// 分钟线：Date 为“日期 时:分”（如 2024-01-05 10:30），接口只保留最近若干交易日
+	KLine1Min  KLinePeriod = 1
+	KLine5Min  KLinePeriod = 5
+	KLine15Min KLinePeriod = 15
+	KLine30Min KLinePeriod = 30
+	KLine60Min KLinePeriod = 60
+)
+
+func (p KLinePeriod) String() string {
+	switch p {
+	case KLineDaily:
+		return "daily"
+	case KLineWeekly:
+		return "weekly"
+	case KLineMonthly:
+		return "monthly"
+	case KLine1Min, KLine5Min, KLine15Min, KLine30Min, KLine60Min:
+		return fmt.Sprintf("%dmin", int(p))
+	}
+	return fmt.Sprintf("klt=%d", int(p))
+}
+
+// valid 是否为支持的周期。
+func (p KLinePeriod) valid() bool {
+	switch p {
+	case KLineDaily, KLineWeekly, KLineMonthly, KLine1Min, KLine5Min, KLine15Min, KLine30Min, KLine60Min:
+		return true
+	}
+	return false
+}
+
+// KLineAdjust 复权方式。零值为前复权，与 GetHisKlines 默认一致。
+type KLineAdjust int
+
+const (
+	AdjustForward  KLineAdjust = iota // 前复权（fqt=1）
+	AdjustNone                        // 不复权（fqt=0）
+	AdjustBackward                    // 后复权（fqt=2）
+)
+
+// fqt 返回东方财富 kline/get 的 fqt 参数。
+func (a KLineAdjust) fqt() int {
+	switch a {
+	case AdjustNone:
+		return 0
+	case AdjustBackward:
+		return 2
+	}
+	return 1
+}
+
+func (a KLineAdjust) String() string {
+	switch a {
+	case AdjustForward:
+		return "forward"
+	case AdjustNone:
+		return "none"
+	case AdjustBackward:
+		return "backward"
+	}
+	return fmt.Sprintf("adjust=%d", int(a))
+}
+
+// KlineOptions GetHisKlinesOpt 的参数：Count 为条数（必填），Period 零值为日线，Adjust 零值为前复权。
+type KlineOptions struct {
+	Count  int
+	Period KLinePeriod
+	Adjust KLineAdjust
+}
+
+// GetHisKlines 拉取 A 股前复权历史日 K 线，count 为条数；使用东方财富 API，fqt=1 前复权，5 秒超时。
+// 错误可用 errors.Is 区分：ErrRequest（网络/HTTP，可重试）、ErrParse、ErrNoData（无该股数据）、ErrEmptyKlines。
 func (c *Client) GetHisKlines(ctx context.Context, code string, count int) ([]model.KLine, error) {
+	return c.GetHisKlinesWithPeriod(ctx, code, count, KLineDaily)
+}
+
+// GetHisKlinesWithPeriod 按周期拉取前复权 K 线（日/周/月/分钟），开启内存缓存（SetKlineTTL）时 TTL 内直接复用。周、月、分钟线与日线返回相同的 fields2 字段顺序
+// （日期,开,收,高,低,量），复用 parseKlinesGJSON 解析；周、月线 Date 为该周期最后一个交易日，分钟线为“日期 时:分”。
+func (c *Client) GetHisKlinesWithPeriod(ctx context.Context, code string, count int, period KLinePeriod) ([]model.KLine, error) {
+	return c.GetHisKlinesOpt(ctx, code, KlineOptions{Count: count, Period: period})
+}
+
+// GetHisKlinesOpt 按周期与复权方式拉取 K 线，回测或对比时可用后复权、不复权。
+// 磁盘增量缓存只用于前复权日线；内存缓存按复权方式分别存放。
+func (c *Client) GetHisKlinesOpt(ctx context.Context, code string, opts KlineOptions) ([]model.KLine, error) {
+	count, period, adjust := opts.Count, opts.Period, opts.Adjust
+	if period == 0 {
+		period = KLineDaily
+	}
 	if code == "" || count <= 0 {
-		return nil, fmt.Errorf("invalid code or count")
+		return nil, fmt.Errorf("%w: code=%q count=%d", ErrInvalidArgument, code, count)
+	}
+	if !period.valid() {
+		return nil, fmt.Errorf("%w: period=%s", ErrInvalidArgument, period)
+	}
+	switch adjust {
+	case AdjustForward, AdjustNone, AdjustBackward:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, adjust)
 	}
-	secid := FormatCode(code)
 	if count > 1000 {
 		count = 1000
 	}
-	url := fmt.Sprintf("%s?secid=%s&fields1=f1,f2,f3,f4,f5,f6&fields2=f51,f52,f53,f54,f55,f56&klt=101&fqt=1&lmt=%d",
-		EastMoneyKLineURL, secid, count)
-	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	key := klineMemoKey(code, period, adjust, count)
+	if ks, ok := c.cachedKlines(ctx, key); ok {
+		return ks, nil
+	}
+	var ks []model.KLine
+	var err error
+	if period == KLineDaily && adjust == AdjustForward && c.KlineCacheDir != "" {
+		ks, err = c.getDailyKlinesCached(ctx, code, count)
+	} else {
+		ks, err = c.fetchKlines(ctx, code, count, period, adjust)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.storeKlines(key, ks)
+	return ks, nil
+}
+
+// fetchKlines 直接请求 K 线接口，不经缓存。
+func (c *Client) fetchKlines(ctx context.Context, code string, count int, period KLinePeriod, adjust KLineAdjust) ([]model.KLine, error) {
+	return c.requestKlines(ctx, FormatCode(code), code, count, period, adjust)
+}
+
+// requestKlines 按 secid 请求 K 线，code 仅用于错误信息与解析结果标注；复权方式记入 trace，便于排查价格对不上的问题。
+func (c *Client) requestKlines(ctx context.Context, secid, code string, count int, period KLinePeriod, adjust KLineAdjust) ([]model.KLine, error) {
+	trace.Debug(ctx, "api: kline %s period=%s adjust=%s(fqt=%d) count=%d", code, period, adjust, adjust.fqt(), count)
+	url := fmt.Sprintf("%s?secid=%s&fields1=f1,f2,f3,f4,f5,f6&fields2=f51,f52,f53,f54,f55,f56&klt=%d&fqt=%d&lmt=%d",
+		c.klineURL(), secid, int(period), adjust.fqt(), count)
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read body: %w", err)
+		return nil, fmt.Errorf("%w: read body: %w", ErrRequest, err)
 	}
 	return parseKlinesGJSON(body, code)
 }
 
+// defaultKlinesBatchWorkers GetHisKlinesBatch 未指定并发时的 goroutine 数
+const defaultKlinesBatchWorkers = 4
+
+// GetHisKlinesBatch 批量拉取多只股票的日 K，返回按代码索引的结果与失败原因。
+// 东方财富 kline/get 只接受单个 secid（ulist 等批量接口仅返回实时快照、不含历史 K 线），
+// 因此按受控并发逐只请求：workers 为拉取 goroutine 数，实际在途请求仍受全局并发信号量与节流约束。
+func (c *Client) GetHisKlinesBatch(ctx context.Context, codes []string, count, workers int) (map[string][]model.KLine, map[string]error) {
+	if workers <= 0 {
+		workers = defaultKlinesBatchWorkers
+	}
+	klines := make(map[string][]model.KLine, len(codes))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	codeCh := make(chan string)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for code := range codeCh {
+				ks, err := c.GetHisKlines(ctx, code, count)
+				mu.Lock()
+				if err != nil {
+					errs[code] = err
+				} else {
+					klines[code] = ks
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, code := range codes {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			errs[code] = ctx.Err()
+			mu.Unlock()
+			continue
+		case codeCh <- code:
+		}
+	}
+	close(codeCh)
+	wg.Wait()
+	trace.Log(ctx, "api: GetHisKlinesBatch codes=%d ok=%d fail=%d", len(codes), len(klines), len(errs))
+	return klines, errs
+}
+
 func parseKlinesGJSON(body []byte, code string) ([]model.KLine, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, fmt.Errorf("%w: klines for %s: %s", ErrParse, code, truncateForLog(body))
+	}
 	klines := gjson.GetBytes(body, "data.klines")
 	if !klines.Exists() || !klines.IsArray() {
-		return nil, fmt.Errorf("api: no data.klines for %s", code)
+		return nil, fmt.Errorf("%w: no data.klines for %s", ErrNoData, code)
 	}
 	arr := klines.Array()
 	out := make([]model.KLine, 0, len(arr))
@@ -570,6 +1057,7 @@ func parseKlinesGJSON(body []byte, code string) ([]model.KLine, error) {
 		}
 		closeVal, _ := strconv.ParseFloat(parts[2], 64)
 		openVal, _ := strconv.ParseFloat(parts[1], 64)
+		highVal, lowVal := parseHighLow(parts[3], parts[4], openVal, closeVal)
 		var vol int64
 		if len(parts) >= 6 {
 			vol, _ = strconv.ParseInt(parts[5], 10, 64)
@@ -578,22 +1066,40 @@ func parseKlinesGJSON(body []byte, code string) ([]model.KLine, error) {
 			Date:   parts[0],
 			Open:   openVal,
 			Close:  closeVal,
+			High:   highVal,
+			Low:    lowVal,
 			Volume: vol,
 		})
 	}
 	if len(out) == 0 {
-		return nil, fmt.Errorf("api: no klines for %s", code)
+		return nil, fmt.Errorf("%w: no klines for %s", ErrEmptyKlines, code)
 	}
 	return out, nil
 }
 
+// parseHighLow 解析最高(f54)、最低(f55)价；缺失或无法解析（如 "-"）时用开收盘价兜底，保证 Low<=High。
+func parseHighLow(highStr, lowStr string, open, close float64) (high, low float64) {
+	high, errH := strconv.ParseFloat(strings.TrimSpace(highStr), 64)
+	low, errL := strconv.ParseFloat(strings.TrimSpace(lowStr), 64)
+	if errH != nil || high <= 0 {
+		high = math.Max(open, close)
+	}
+	if errL != nil || low <= 0 {
+		low = math.Min(open, close)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	return high, low
+}
+
 func (c *Client) GetKLines(ctx context.Context, code string) ([]model.KLine, error) {
 	return c.GetHisKlines(ctx, code, 30)
 }
 
 // GetIndexQuotes 获取今日大盘指数：上证、深证成指、创业板指（用于启动问候邮件）。
 func (c *Client) GetIndexQuotes(ctx context.Context) ([]model.IndexQuote, error) {
-	url := fmt.Sprintf("%s?secids=%s&fields=%s", EastMoneyIndexURL, indexSecIDs, indexFields)
+	url := fmt.Sprintf("%s?secids=%s&fields=%s", c.indexURL(), indexSecIDs, indexFields)
 	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
 	if err != nil {
 		return nil, err
@@ -606,6 +1112,33 @@ func (c *Client) GetIndexQuotes(ctx context.Context) ([]model.IndexQuote, error)
 	return parseIndexQuotesGJSON(body)
 }
 
+// GetIndexKlines 拉取大盘指数日 K（用于择时），code 为 GetIndexQuotes 返回的指数代码（如 000001 上证指数）。
+// 指数与个股代码会重叠（000001 也是平安银行），按 indexSecIDs 映射市场，不在其中的代码返回 ErrInvalidArgument。
+func (c *Client) GetIndexKlines(ctx context.Context, code string, count int) ([]model.KLine, error) {
+	secid, ok := indexSecID(code)
+	if !ok || count <= 0 {
+		return nil, fmt.Errorf("%w: index code=%q count=%d", ErrInvalidArgument, code, count)
+	}
+	if count > 1000 {
+		count = 1000
+	}
+	return c.requestKlines(ctx, secid, code, count, KLineDaily, AdjustForward)
+}
+
+// indexSecID 在 indexSecIDs 中查找指数代码对应的 secid。
+func indexSecID(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", false
+	}
+	for _, id := range strings.Split(indexSecIDs, ",") {
+		if strings.HasSuffix(id, "."+code) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
 func parseIndexQuotesGJSON(body []byte) ([]model.IndexQuote, error) {
 	diff := gjson.GetBytes(body, "data.diff")
 	if !diff.Exists() || !diff.IsArray() {
@@ -626,26 +1159,109 @@ func parseIndexQuotesGJSON(body []byte) ([]model.IndexQuote, error) {
 		if rawF3 > 20 || rawF3 < -20 {
 			changePct = rawF3 / indexChangePctDivisor
 		}
+		// 今开、昨收缺失或未开盘时接口返回 "-"，解析为 0，展示时省略
 		out = append(out, model.IndexQuote{
 			Code:      code,
 			Name:      name,
 			Price:     price,
 			ChangePct: changePct,
+			Open:      v.Get("f17").Float(),
+			PrevClose: v.Get("f18").Float(),
 		})
 	}
 	return out, nil
 }
 
-// FormatCode 转为东方财富 secid：上海 0.600519，深圳 1.000001
+// GetIndustryBoards 拉取行业板块当日涨幅榜（按涨幅降序），Rank 从 1 开始。
+func (c *Client) GetIndustryBoards(ctx context.Context) ([]model.IndustryBoard, error) {
+	url := fmt.Sprintf("%s?pn=1&pz=%d&po=1&fid=f3&fs=%s&fields=%s",
+		c.listURL(), listPageSize, fsIndustryBoards, industryBoardsFields)
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read industry body: %w", err)
+	}
+	return parseIndustryBoardsGJSON(body)
+}
+
+func parseIndustryBoardsGJSON(body []byte) ([]model.IndustryBoard, error) {
+	diff := gjson.GetBytes(body, "data.diff")
+	if !diff.Exists() || !(diff.IsArray() || diff.IsObject()) {
+		return nil, fmt.Errorf("api: no data.diff for industry boards")
+	}
+	var out []model.IndustryBoard
+	diff.ForEach(func(_, v gjson.Result) bool {
+		name := strings.TrimSpace(v.Get("f14").String())
+		if name == "" {
+			return true
+		}
+		out = append(out, model.IndustryBoard{
+			Code:      strings.TrimSpace(v.Get("f12").String()),
+			Name:      name,
+			ChangePct: v.Get("f3").Float(),
+		})
+		return true
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ChangePct > out[j].ChangePct })
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out, nil
+}
+
+// GetConceptBoards 拉取概念板块当日涨幅榜（按涨幅降序，Rank 从 1 开始），conceptCacheTTL 内复用上次结果。
+func (c *Client) GetConceptBoards(ctx context.Context) ([]model.ConceptBoard, error) {
+	c.conceptMu.Lock()
+	defer c.conceptMu.Unlock()
+	if c.conceptBoards != nil && time.Since(c.conceptAt) < conceptCacheTTL {
+		return c.conceptBoards, nil
+	}
+	var out []model.ConceptBoard
+	for page := 1; page <= maxListPages; page++ {
+		url := fmt.Sprintf("%s?pn=%d&pz=%d&po=1&fid=f3&fs=%s&fields=%s",
+			c.listURL(), page, listPageSize, fsConceptBoards, industryBoardsFields)
+		resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read concept body: %w", err)
+		}
+		boards, err := parseIndustryBoardsGJSON(body)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, boards...)
+		total := int(gjson.GetBytes(body, "data.total").Int())
+		if len(boards) < listPageSize || len(out) >= total {
+			break
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ChangePct > out[j].ChangePct })
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	c.conceptBoards, c.conceptAt = out, time.Now()
+	return out, nil
+}
+
+// FormatCode 转为东方财富 secid（市场.代码）：上海（主板 60、科创板 688、基金 5、B 股 9）为 1.，
+// 如 1.600519、1.688981；深圳与北交所（含 92 开头）为 0.，如 0.000001、0.300750。
 func FormatCode(code string) string {
 	code = strings.TrimSpace(code)
 	if code == "" {
 		return "0.000000"
 	}
-	if code[0] == '6' || code[0] == '5' || code[0] == '9' {
-		return "0." + code
+	if model.BoardOf(code) != model.BoardBSE && (code[0] == '6' || code[0] == '5' || code[0] == '9') {
+		return "1." + code
 	}
-	return "1." + code
+	return "0." + code
 }
 
 func secID(code string) string {
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"stockMaxWin/internal/model"
+)
+
+// klineFixtures 各周期的固定 JSON 样本（testdata/klines_*.json），字段顺序同 fields2=f51..f56 及其后的扩展字段。
+var klineFixtures = []struct {
+	name  string
+	file  string
+	count int
+	first model.KLine
+	last  string
+}{
+	{"日线", "klines_daily.json", 3, model.KLine{Date: "2024-01-02", Open: 1688, Close: 1685.01, High: 1706.98, Low: 1680, Volume: 32154}, "2024-01-04"},
+	{"周线", "klines_weekly.json", 2, model.KLine{Date: "2024-01-05", Open: 1688, Close: 1663.36, High: 1706.98, Low: 1650.17, Volume: 106420}, "2024-01-12"},
+	{"月线", "klines_monthly.json", 3, model.KLine{Date: "2024-01-31", Open: 1688, Close: 1621.17, High: 1706.98, Low: 1562, Volume: 412630}, "2024-03-29"},
+	{"5 分钟线", "klines_5min.json", 4, model.KLine{Date: "2024-01-05 09:35", Open: 1669, Close: 1671.5, High: 1673, Low: 1666.66, Volume: 2135}, "2024-01-05 09:50"},
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParseKlinesGJSONPeriods(t *testing.T) {
+	for _, tt := range klineFixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := parseKlinesGJSON(readFixture(t, tt.file), "600519")
+			if err != nil {
+				t.Fatalf("parseKlinesGJSON() err = %v", err)
+			}
+			if len(ks) != tt.count {
+				t.Fatalf("len = %d, want %d", len(ks), tt.count)
+			}
+			if ks[0] != tt.first {
+				t.Errorf("first = %+v, want %+v", ks[0], tt.first)
+			}
+			if got := ks[len(ks)-1].Date; got != tt.last {
+				t.Errorf("last date = %q, want %q", got, tt.last)
+			}
+		})
+	}
+}
+
+func TestParseKlinesGJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"非 JSON", `not json`, ErrParse},
+		{"无 data", `{"rc":0,"data":null}`, ErrNoData},
+		{"空数组", `{"rc":0,"data":{"klines":[]}}`, ErrEmptyKlines},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseKlinesGJSON([]byte(tt.body), "600519"); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetHisKlinesWithPeriodKlt 各周期映射到请求的 klt 参数，按 klt 返回对应样本并解析。
+func TestGetHisKlinesWithPeriodKlt(t *testing.T) {
+	files := map[string]string{"101": "klines_daily.json", "102": "klines_weekly.json", "103": "klines_monthly.json"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := files[r.URL.Query().Get("klt")]
+		if !ok {
+			file = "klines_5min.json"
+		}
+		w.Write(readFixture(t, file))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		period KLinePeriod
+		klt    int
+		count  int
+	}{
+		{KLineDaily, 101, 3},
+		{KLineWeekly, 102, 2},
+		{KLineMonthly, 103, 3},
+		{KLine1Min, 1, 4},
+		{KLine5Min, 5, 4},
+		{KLine15Min, 15, 4},
+		{KLine30Min, 30, 4},
+		{KLine60Min, 60, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period.String(), func(t *testing.T) {
+			if int(tt.period) != tt.klt {
+				t.Errorf("klt = %d, want %d", int(tt.period), tt.klt)
+			}
+			var gotKlt string
+			c := NewClientWithOptions(ClientOptions{Limits: Limits{RPS: 1000, Burst: 10}})
+			c.KLineURL = srv.URL
+			c.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				gotKlt = r.URL.Query().Get("klt")
+				return http.DefaultTransport.RoundTrip(r)
+			})
+			ks, err := c.GetHisKlinesWithPeriod(context.Background(), "600519", 10, tt.period)
+			if err != nil {
+				t.Fatalf("GetHisKlinesWithPeriod() err = %v", err)
+			}
+			if gotKlt != strconv.Itoa(tt.klt) {
+				t.Errorf("request klt = %q, want %d", gotKlt, tt.klt)
+			}
+			if len(ks) != tt.count {
+				t.Errorf("len = %d, want %d", len(ks), tt.count)
+			}
+		})
+	}
+
+	t.Run("不支持的周期", func(t *testing.T) {
+		c := NewClientWithOptions(ClientOptions{})
+		c.KLineURL = srv.URL
+		if _, err := c.GetHisKlinesWithPeriod(context.Background(), "600519", 10, KLinePeriod(104)); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("err = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Endpoints 行情接口地址覆盖：可填完整 URL、scheme://host 或仅 host（如 push2.eastmoney.com、自建镜像 127.0.0.1:8080）。
+// 只给 host 时沿用默认地址的 scheme 与路径；为空则保持默认地址。
+type Endpoints struct {
+	List  string // 列表/板块接口，默认 EastMoneyListURL
+	KLine string // K 线接口，默认 EastMoneyKLineURL
+	Index string // 指数接口，默认 EastMoneyIndexURL
+}
+
+// SetEndpoints 按 e 覆盖 Client 的接口地址，任一项无效时返回错误且不做任何修改。
+func (c *Client) SetEndpoints(e Endpoints) error {
+	list, err := resolveEndpoint(EastMoneyListURL, e.List)
+	if err != nil {
+		return fmt.Errorf("list: %w", err)
+	}
+	kline, err := resolveEndpoint(EastMoneyKLineURL, e.KLine)
+	if err != nil {
+		return fmt.Errorf("kline: %w", err)
+	}
+	index, err := resolveEndpoint(EastMoneyIndexURL, e.Index)
+	if err != nil {
+		return fmt.Errorf("index: %w", err)
+	}
+	c.ListURL, c.KLineURL, c.IndexURL = list, kline, index
+	return nil
+}
+
+// resolveEndpoint 把覆盖值合并到默认地址：未带 scheme 的补默认 scheme，未带路径的补默认路径。
+func resolveEndpoint(def, override string) (string, error) {
+	override = strings.TrimSpace(override)
+	if override == "" {
+		return def, nil
+	}
+	base, err := url.Parse(def)
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(override, "://") {
+		override = base.Scheme + "://" + override
+	}
+	u, err := url.Parse(override)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("地址缺少 host: %q", override)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = base.Path
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String(), nil
+}
+
+func (c *Client) listURL() string {
+	if c.ListURL != "" {
+		return c.ListURL
+	}
+	return EastMoneyListURL
+}
+
+func (c *Client) klineURL() string {
+	if c.KLineURL != "" {
+		return c.KLineURL
+	}
+	return EastMoneyKLineURL
+}
+
+func (c *Client) indexURL() string {
+	if c.IndexURL != "" {
+		return c.IndexURL
+	}
+	return EastMoneyIndexURL
+}
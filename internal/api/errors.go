@@ -0,0 +1,13 @@
+package api
+
+import "errors"
+
+// GetHisKlines 等接口返回的错误类别，调用方用 errors.Is 区分：
+// 网络/HTTP 失败值得稍后重试；无数据、空 K 线是确定结果，重试无意义。
+var (
+	ErrInvalidArgument = errors.New("api: invalid argument")
+	ErrRequest         = errors.New("api: request failed")
+	ErrParse           = errors.New("api: malformed response")
+	ErrNoData          = errors.New("api: no data")
+	ErrEmptyKlines     = errors.New("api: empty klines")
+)
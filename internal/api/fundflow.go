@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"stockMaxWin/internal/model"
+)
+
+// 个股日资金流接口：fields2 f51 日期 f52 主力净流入(元) f53 小单 f54 中单 f55 大单 f56 超大单
+const (
+	EastMoneyFundFlowURL = "https://push2his.eastmoney.com/api/qt/stock/fflow/daykline/get"
+	fundFlowFields2      = "f51,f52,f53,f54,f55,f56"
+	maxFundFlowDays      = 100
+)
+
+// 个股实时资金流：stock/get 接口 f57 代码 f135 主力流入 f136 主力流出 f137 主力净流入(元)，无数据时字段为 "-"
+const (
+	EastMoneyStockURL = "https://push2.eastmoney.com/api/qt/stock/get"
+	moneyFlowFields   = "f57,f135,f136,f137"
+)
+
+// GetMoneyFlow 拉取个股当日主力资金流，比列表接口的 f62/f184/f66 稳定（列表字段常返回空）。
+// 错误语义同 GetHisKlines：ErrRequest、ErrParse，接口无数据或字段为 "-" 时 ErrNoData。
+func (c *Client) GetMoneyFlow(ctx context.Context, code string) (model.MoneyFlow, error) {
+	if code == "" {
+		return model.MoneyFlow{}, fmt.Errorf("%w: code is empty", ErrInvalidArgument)
+	}
+	url := fmt.Sprintf("%s?secid=%s&fields=%s", EastMoneyStockURL, FormatCode(code), moneyFlowFields)
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return model.MoneyFlow{}, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return model.MoneyFlow{}, fmt.Errorf("%w: read body: %w", ErrRequest, err)
+	}
+	return parseMoneyFlowGJSON(body, code)
+}
+
+func parseMoneyFlowGJSON(body []byte, code string) (model.MoneyFlow, error) {
+	if !gjson.ValidBytes(body) {
+		return model.MoneyFlow{}, fmt.Errorf("%w: money flow for %s: %s", ErrParse, code, truncateForLog(body))
+	}
+	data := gjson.GetBytes(body, "data")
+	in, out, net := data.Get("f135"), data.Get("f136"), data.Get("f137")
+	if in.Type != gjson.Number || out.Type != gjson.Number || net.Type != gjson.Number {
+		return model.MoneyFlow{}, fmt.Errorf("%w: no money flow for %s", ErrNoData, code)
+	}
+	return model.MoneyFlow{Code: code, MainInflow: in.Float(), MainOutflow: out.Float(), MainNetInflow: net.Float()}, nil
+}
+
+// GetFundFlowHistory 拉取个股近 days 个交易日的主力净流入（按日期升序，最后一条为最近交易日）。
+// 错误语义同 GetHisKlines：ErrRequest、ErrParse、ErrNoData。
+func (c *Client) GetFundFlowHistory(ctx context.Context, code string, days int) ([]model.FundFlowDay, error) {
+	if code == "" || days <= 0 {
+		return nil, fmt.Errorf("%w: code=%q days=%d", ErrInvalidArgument, code, days)
+	}
+	if days > maxFundFlowDays {
+		days = maxFundFlowDays
+	}
+	url := fmt.Sprintf("%s?secid=%s&klt=101&lmt=%d&fields1=f1,f2,f3,f7&fields2=%s",
+		EastMoneyFundFlowURL, FormatCode(code), days, fundFlowFields2)
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read body: %w", ErrRequest, err)
+	}
+	return parseFundFlowGJSON(body, code)
+}
+
+func parseFundFlowGJSON(body []byte, code string) ([]model.FundFlowDay, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, fmt.Errorf("%w: fund flow for %s: %s", ErrParse, code, truncateForLog(body))
+	}
+	klines := gjson.GetBytes(body, "data.klines")
+	if !klines.Exists() || !klines.IsArray() {
+		return nil, fmt.Errorf("%w: no fund flow for %s", ErrNoData, code)
+	}
+	var out []model.FundFlowDay
+	for _, v := range klines.Array() {
+		parts := strings.Split(strings.TrimSpace(v.String()), ",")
+		if len(parts) < 2 {
+			continue
+		}
+		mainNet, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, model.FundFlowDay{Date: parts[0], MainNetInflow: mainNet})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: empty fund flow for %s", ErrNoData, code)
+	}
+	return out, nil
+}
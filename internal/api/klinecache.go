@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 日 K 磁盘增量缓存：每只股票一个 JSON 文件，命中时只拉最近 klineCacheOverlap 根 + 上次缓存后的天数，
+// 与缓存重叠区逐根比对收盘价；不一致说明期间发生除权、前复权价整体变动，整只失效全量重拉。
+const (
+	klineCacheOverlap  = 5
+	klineCacheMaxBars  = 1000
+	klineCloseEpsilon  = 0.005 // 前复权价保留两位小数，差值超过半分才算变动
+	klineCacheDirPerm  = 0o755
+	klineCacheFilePerm = 0o644
+	klineDateLayout    = "2006-01-02"
+)
+
+type klineCacheFile struct {
+	Klines []model.KLine `json:"klines"`
+}
+
+// SetKlineCacheDir 开启日 K 磁盘增量缓存，dir 为空则关闭。
+func (c *Client) SetKlineCacheDir(dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, klineCacheDirPerm); err != nil {
+			return err
+		}
+	}
+	c.KlineCacheDir = dir
+	return nil
+}
+
+// getDailyKlinesCached 日 K 增量拉取：缓存不足、与新数据无重叠或检测到除权时全量拉取并覆盖缓存。
+func (c *Client) getDailyKlinesCached(ctx context.Context, code string, count int) ([]model.KLine, error) {
+	path := filepath.Join(c.KlineCacheDir, code+".json")
+	cached := loadKlineCache(path)
+	if len(cached) < count {
+		return c.refreshKlineCache(ctx, path, code, count, "miss")
+	}
+	n := klineCacheOverlap + daysSince(cached[len(cached)-1].Date)
+	if n > count {
+		return c.refreshKlineCache(ctx, path, code, count, "stale")
+	}
+	fresh, err := c.fetchKlines(ctx, code, n, KLineDaily, AdjustForward)
+	if err != nil {
+		return nil, err
+	}
+	merged, ok := mergeKlines(cached, fresh)
+	if !ok {
+		trace.Log(ctx, "api: kline cache %s 与缓存无重叠或重叠区收盘价不一致（疑似除权），整体失效重拉", code)
+		return c.refreshKlineCache(ctx, path, code, count, "ex-rights")
+	}
+	trace.Debug(ctx, "api: kline cache hit %s cached=%d fetched=%d", code, len(cached), len(fresh))
+	saveKlineCache(ctx, path, merged)
+	return tailKlines(merged, count), nil
+}
+
+func (c *Client) refreshKlineCache(ctx context.Context, path, code string, count int, reason string) ([]model.KLine, error) {
+	ks, err := c.fetchKlines(ctx, code, count, KLineDaily, AdjustForward)
+	if err != nil {
+		return nil, err
+	}
+	trace.Debug(ctx, "api: kline cache full fetch %s reason=%s bars=%d", code, reason, len(ks))
+	saveKlineCache(ctx, path, ks)
+	return ks, nil
+}
+
+// mergeKlines 用 fresh 覆盖 cached 中同日期及之后的部分。重叠区（不含缓存最后一根，它可能是盘中未收盘的数据）
+// 收盘价不一致或两段没有重叠时返回 ok=false，调用方应全量重拉。
+func mergeKlines(cached, fresh []model.KLine) ([]model.KLine, bool) {
+	if len(fresh) == 0 {
+		return nil, false
+	}
+	byDate := make(map[string]float64, len(cached))
+	for _, k := range cached[:len(cached)-1] {
+		byDate[k.Date] = k.Close
+	}
+	last := cached[len(cached)-1].Date
+	if fresh[0].Date > last {
+		return nil, false
+	}
+	for _, k := range fresh {
+		if old, ok := byDate[k.Date]; ok && math.Abs(old-k.Close) > klineCloseEpsilon {
+			return nil, false
+		}
+	}
+	i := len(cached)
+	for i > 0 && cached[i-1].Date >= fresh[0].Date {
+		i--
+	}
+	merged := append(append([]model.KLine(nil), cached[:i]...), fresh...)
+	return tailKlines(merged, klineCacheMaxBars), true
+}
+
+func tailKlines(ks []model.KLine, n int) []model.KLine {
+	if len(ks) > n {
+		return ks[len(ks)-n:]
+	}
+	return ks
+}
+
+// daysSince 距 date 的自然日数（交易日数的上界），解析失败返回一个足够大的值以触发全量拉取。
+func daysSince(date string) int {
+	t, err := time.ParseInLocation(klineDateLayout, date, time.Local)
+	if err != nil {
+		return klineCacheMaxBars
+	}
+	d := int(time.Since(t).Hours() / 24)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func loadKlineCache(path string) []model.KLine {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var f klineCacheFile
+	if json.Unmarshal(data, &f) != nil {
+		return nil
+	}
+	return f.Klines
+}
+
+// saveKlineCache 先写临时文件再 rename，并发拉同一只或进程中途退出都不会留下半截文件；失败只记日志。
+func saveKlineCache(ctx context.Context, path string, ks []model.KLine) {
+	if err := writeKlineCache(path, ks); err != nil {
+		trace.Log(ctx, "api: 写 kline cache 失败 %s err=%v", path, err)
+	}
+}
+
+func writeKlineCache(path string, ks []model.KLine) error {
+	data, err := json.Marshal(klineCacheFile{Klines: ks})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), klineCacheFilePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
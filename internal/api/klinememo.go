@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// K 线内存缓存：同一 code+period+count 在 TTL 内直接复用，调度模式每轮只需补拉过期的；
+// 条目数超过 klineMemoSweepSize 时写入前顺带清掉已过期条目，避免常驻进程无限增长
+const (
+	DefaultKlineTTL    = 20 * time.Minute
+	klineMemoSweepSize = 4096
+)
+
+type klineMemoEntry struct {
+	klines []model.KLine
+	at     time.Time
+}
+
+func klineMemoKey(code string, period KLinePeriod, adjust KLineAdjust, count int) string {
+	return fmt.Sprintf("%s|%d|%d|%d", code, int(period), int(adjust), count)
+}
+
+// SetKlineTTL 设置 K 线内存缓存有效期，<=0 关闭并清空缓存。
+func (c *Client) SetKlineTTL(ttl time.Duration) {
+	c.klineMu.Lock()
+	defer c.klineMu.Unlock()
+	if ttl < 0 {
+		ttl = 0
+	}
+	c.klineTTL = ttl
+	if ttl == 0 {
+		c.klineMemo = nil
+	}
+}
+
+// cachedKlines 查内存缓存，未开启或未命中返回 ok=false。返回副本，调用方修改不影响缓存。
+func (c *Client) cachedKlines(ctx context.Context, key string) ([]model.KLine, bool) {
+	c.klineMu.Lock()
+	e, ok := c.klineMemo[key]
+	ttl := c.klineTTL
+	c.klineMu.Unlock()
+	if ttl == 0 {
+		return nil, false
+	}
+	if !ok || time.Since(e.at) >= ttl {
+		trace.Debug(ctx, "api: kline memo miss %s", key)
+		return nil, false
+	}
+	trace.Debug(ctx, "api: kline memo hit %s age=%s", key, time.Since(e.at).Round(time.Second))
+	return append([]model.KLine(nil), e.klines...), true
+}
+
+func (c *Client) storeKlines(key string, ks []model.KLine) {
+	c.klineMu.Lock()
+	defer c.klineMu.Unlock()
+	if c.klineTTL == 0 {
+		return
+	}
+	if c.klineMemo == nil {
+		c.klineMemo = make(map[string]klineMemoEntry)
+	}
+	now := time.Now()
+	if len(c.klineMemo) >= klineMemoSweepSize {
+		for k, e := range c.klineMemo {
+			if now.Sub(e.at) >= c.klineTTL {
+				delete(c.klineMemo, k)
+			}
+		}
+	}
+	c.klineMemo[key] = klineMemoEntry{klines: append([]model.KLine(nil), ks...), at: now}
+}
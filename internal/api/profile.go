@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 公司概况接口（F10）：code 带市场前缀（SH600000 / SZ000001 / BJ830799）；
+// jbzl 基本资料中 EM2016 为东财行业（如“金融-银行-股份制银行”），INDUSTRYCSRC1 为证监会行业；
+// MAIN_BUSINESS 为主营业务，缺失时退回经营范围 BUSINESS_SCOPE 并截断
+const (
+	EastMoneyProfileURL  = "https://emweb.securities.eastmoney.com/PC_HSF10/CompanySurvey/PageAjax"
+	profileBusinessRunes = 60
+)
+
+// GetStockProfile 拉取公司概况的行业与主营；同一代码成功一次后在进程内缓存（概况极少变化）。
+// 错误语义同 GetHisKlines：ErrRequest、ErrParse、ErrNoData。
+func (c *Client) GetStockProfile(ctx context.Context, code string) (model.StockProfile, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return model.StockProfile{}, fmt.Errorf("%w: code=%q", ErrInvalidArgument, code)
+	}
+	c.profileMu.Lock()
+	p, ok := c.profiles[code]
+	c.profileMu.Unlock()
+	if ok {
+		trace.Debug(ctx, "api: profile cache hit %s", code)
+		return p, nil
+	}
+	url := fmt.Sprintf("%s?code=%s", EastMoneyProfileURL, profileCode(code))
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return model.StockProfile{}, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return model.StockProfile{}, fmt.Errorf("%w: read body: %w", ErrRequest, err)
+	}
+	p, err = parseProfileGJSON(body, code)
+	if err != nil {
+		return model.StockProfile{}, err
+	}
+	c.profileMu.Lock()
+	if c.profiles == nil {
+		c.profiles = make(map[string]model.StockProfile)
+	}
+	c.profiles[code] = p
+	c.profileMu.Unlock()
+	return p, nil
+}
+
+// profileCode 6 位代码转 F10 接口使用的 SH/SZ/BJ 前缀形式。
+func profileCode(code string) string {
+	switch {
+	case model.BoardOf(code) == model.BoardBSE:
+		return "BJ" + code
+	case strings.HasPrefix(FormatCode(code), "1."):
+		return "SH" + code
+	}
+	return "SZ" + code
+}
+
+func parseProfileGJSON(body []byte, code string) (model.StockProfile, error) {
+	if !gjson.ValidBytes(body) {
+		return model.StockProfile{}, fmt.Errorf("%w: profile for %s: %s", ErrParse, code, truncateForLog(body))
+	}
+	info := gjson.GetBytes(body, "jbzl.0")
+	if !info.Exists() {
+		return model.StockProfile{}, fmt.Errorf("%w: profile for %s", ErrNoData, code)
+	}
+	p := model.StockProfile{Code: code, Industry: strings.TrimSpace(info.Get("EM2016").String())}
+	if p.Industry == "" {
+		p.Industry = strings.TrimSpace(info.Get("INDUSTRYCSRC1").String())
+	}
+	p.MainBusiness = strings.TrimSpace(info.Get("MAIN_BUSINESS").String())
+	if p.MainBusiness == "" {
+		p.MainBusiness = truncateRunes(strings.TrimSpace(info.Get("BUSINESS_SCOPE").String()), profileBusinessRunes)
+	}
+	return p, nil
+}
+
+// truncateRunes 按字符截断，超出时以省略号结尾。
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 按代码查行情：ulist 接口按 secids 批量返回，字段与列表接口一致（listFieldsMainBoard）；
+// fltt=2 让价格、涨跌幅等直接返回小数（ulist 默认返回放大后的整数，见 indexChangePctDivisor）。
+// 单次 secids 过长会被截断，按 quotesByCodesBatch 分批请求
+const quotesByCodesBatch = 100
+
+// GetQuotesByCodes 按代码拉取行情，字段同 GetMainBoardQuotes，供自选股模式跳过全量列表只分析指定股票。
+// 返回顺序同接口（不保证与 codes 一致）；接口未返回的代码（退市、代码错误）不出现在结果中，只记日志。
+// 单批失败只记日志，全部失败才返回错误；codes 为空返回 ErrInvalidArgument。
+func (c *Client) GetQuotesByCodes(ctx context.Context, codes []string) ([]model.StockQuote, error) {
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%w: codes is empty", ErrInvalidArgument)
+	}
+	var list []model.StockQuote
+	var lastErr error
+	for start := 0; start < len(codes); start += quotesByCodesBatch {
+		end := min(start+quotesByCodesBatch, len(codes))
+		part, err := c.fetchQuotesByCodes(ctx, codes[start:end])
+		if err != nil {
+			lastErr = err
+			trace.Log(ctx, "api: GetQuotesByCodes 第 %d-%d 只失败，跳过 err=%v", start+1, end, err)
+			continue
+		}
+		list = append(list, part...)
+	}
+	if list == nil && lastErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, lastErr)
+	}
+	if len(list) < len(codes) {
+		got := make(map[string]bool, len(list))
+		for i := range list {
+			got[list[i].Code] = true
+		}
+		var missing []string
+		for _, code := range codes {
+			if !got[code] {
+				missing = append(missing, code)
+			}
+		}
+		trace.Log(ctx, "api: GetQuotesByCodes 未返回 %d 只：%s", len(missing), strings.Join(missing, ","))
+	}
+	trace.Log(ctx, "api: GetQuotesByCodes done len=%d/%d", len(list), len(codes))
+	return list, nil
+}
+
+func (c *Client) fetchQuotesByCodes(ctx context.Context, codes []string) ([]model.StockQuote, error) {
+	secids := make([]string, len(codes))
+	for i, code := range codes {
+		secids[i] = secID(code)
+	}
+	url := fmt.Sprintf("%s?fltt=2&secids=%s&fields=%s", c.indexURL(), strings.Join(secids, ","), listFieldsMainBoard)
+	trace.Debug(ctx, "api: GetQuotesByCodes url=%s", url)
+	resp, err := c.doWithRetry(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var list []model.StockQuote
+	if _, _, err := decodeQuoteListStream(ctx, resp.Body, &list); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	return list, nil
+}
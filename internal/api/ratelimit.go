@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 令牌桶限流（默认）：按 RPS 匀速发放令牌，桶容量即突发量。STOCKMAXWIN_API_RPS=速率[,突发] 配置，
+// 设为 0 时回退旧的固定间隔 + 抖动（STOCKMAXWIN_API_DELAY_MS / STOCKMAXWIN_API_JITTER_MS），便于对比
+const (
+	envAPIRPS       = "STOCKMAXWIN_API_RPS"
+	defaultAPIRPS   = 5.0 // 与默认固定间隔 200ms 相当
+	defaultAPIBurst = 1
+	// RPSFixedGap 作为 Limits.RPS 传入时关闭令牌桶、改用固定间隔节流
+	RPSFixedGap = -1
+)
+
+// tokenBucket 令牌桶：tokens 可为负，表示已被预约、需等待补足；每次 Wait 只在锁内计算等待时长，锁外睡眠。
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // 每秒令牌数
+	burst    float64
+	tokens   float64
+	last     time.Time
+	slowdown float64 // 429 自适应降速倍数，实际发放速率为 rate/slowdown；<=1 不降速
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// setSlowdown 设置降速倍数，之后发放的令牌按 rate/f 补充。
+func (b *tokenBucket) setSlowdown(f float64) {
+	b.mu.Lock()
+	b.slowdown = f
+	b.mu.Unlock()
+}
+
+// reserve 取走一个令牌，返回需等待的时长。
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rate := b.rate
+	if b.slowdown > 1 {
+		rate /= b.slowdown
+	}
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// cancel 等待被 ctx 打断时归还预约的令牌。
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
+
+// Wait 阻塞到拿到令牌或 ctx 取消。
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	d := b.reserve()
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// ParseRPS 解析 "速率" 或 "速率,突发"（如 "5"、"5,10"）；速率 0 表示回退固定间隔，突发缺省为 1。
+func ParseRPS(s string) (rps float64, burst int, err error) {
+	rateStr, burstStr, hasBurst := strings.Cut(strings.TrimSpace(s), ",")
+	rps, err = strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil || rps < 0 {
+		return 0, 0, fmt.Errorf("%w: rps=%q", ErrInvalidArgument, s)
+	}
+	burst = defaultAPIBurst
+	if hasBurst {
+		burst, err = strconv.Atoi(strings.TrimSpace(burstStr))
+		if err != nil || burst < 1 {
+			return 0, 0, fmt.Errorf("%w: burst=%q", ErrInvalidArgument, s)
+		}
+	}
+	return rps, burst, nil
+}
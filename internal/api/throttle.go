@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"stockMaxWin/internal/trace"
+)
+
+// 429 自适应降速：被限流时把请求间隔翻倍（令牌桶为速率减半），最多放慢 adaptiveMaxSlowdown 倍；
+// 同一波并发请求几乎同时收到的 429 只算一次（距上次调整 adaptiveSlowdownWindow 内不重复翻倍），持续 429 时每个窗口翻倍一次；
+// 距最近一次 429 与上次调整均满 adaptiveRecoverAfter 时，成功请求把倍数乘以 adaptiveRecoverRatio，逐步恢复到配置速率
+const (
+	adaptiveSlowdownFactor = 2.0
+	adaptiveMaxSlowdown    = 16.0
+	adaptiveSlowdownWindow = 2 * time.Second
+	adaptiveRecoverAfter   = 30 * time.Second
+	adaptiveRecoverRatio   = 0.75
+)
+
+// throttle 一组请求节流状态：令牌桶（或固定间隔 + 抖动）与并发信号量。
+// 包级 defaultThrottle 由环境变量初始化，NewClient 创建的客户端共享（CurrentLimits/SetLimits 作用于它）；
+// NewClientWithOptions 为客户端创建独立的 throttle，互不影响。
+type throttle struct {
+	mu            sync.Mutex
+	bucket        *tokenBucket // nil 时按固定间隔 + 抖动节流
+	gap           time.Duration
+	jitterMS      int
+	maxConcurrent int
+	sem           chan struct{}
+
+	// 429 自适应降速状态（受 mu 保护）：slowdown 为当前倍数（1 不降速），limitedAt 最近一次计入的 429，adjustedAt 最近一次调整
+	slowdown   float64
+	limitedAt  time.Time
+	adjustedAt time.Time
+
+	lastMu  sync.Mutex
+	lastReq time.Time
+}
+
+var defaultThrottle = newThrottle()
+
+// clock 自适应降速的时间来源，测试时可替换为假时钟。
+var clock = time.Now
+
+// newThrottle 内置默认值：令牌桶 defaultAPIRPS、固定间隔与抖动备用、并发 defaultMaxConcurrent。
+func newThrottle() *throttle {
+	return &throttle{
+		bucket:        newTokenBucket(defaultAPIRPS, defaultAPIBurst),
+		gap:           defaultRequestGap,
+		jitterMS:      defaultRequestJitter,
+		maxConcurrent: defaultMaxConcurrent,
+		sem:           make(chan struct{}, defaultMaxConcurrent),
+		slowdown:      1,
+	}
+}
+
+func (t *throttle) limits() Limits {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := Limits{RPS: RPSFixedGap, RequestGap: t.gap, JitterMS: t.jitterMS, MaxConcurrent: t.maxConcurrent}
+	if t.bucket != nil {
+		l.RPS, l.Burst = t.bucket.rate, int(t.bucket.burst)
+	}
+	return l
+}
+
+// set 按 SetLimits 的语义调整：rps>0 换用新的令牌桶，rps<0（RPSFixedGap）关闭令牌桶，0 保持不变；
+// 并发上限变化时替换信号量，进行中的请求仍归还到原信号量。
+func (t *throttle) set(l Limits) Limits {
+	t.mu.Lock()
+	switch {
+	case l.RPS > 0:
+		t.bucket = newTokenBucket(l.RPS, l.Burst)
+		t.bucket.setSlowdown(t.slowdown)
+	case l.RPS < 0:
+		t.bucket = nil
+	}
+	if l.RequestGap > 0 {
+		t.gap = l.RequestGap
+	}
+	if l.JitterMS >= 0 {
+		t.jitterMS = l.JitterMS
+	}
+	if n := l.MaxConcurrent; n > 0 {
+		if n > maxConcurrentCap {
+			n = maxConcurrentCap
+		}
+		if n != t.maxConcurrent {
+			t.maxConcurrent = n
+			t.sem = make(chan struct{}, n)
+		}
+	}
+	t.mu.Unlock()
+	return t.limits()
+}
+
+func (t *throttle) currentSem() chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sem
+}
+
+// wait 每次发请求前调用：令牌桶开启时等令牌，否则按固定间隔 + 抖动节流。
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	b := t.bucket
+	t.mu.Unlock()
+	if b != nil {
+		return b.Wait(ctx)
+	}
+	t.pace(ctx)
+	return ctx.Err()
+}
+
+func (t *throttle) pace(ctx context.Context) {
+	t.mu.Lock()
+	gap, jitter := t.gap, t.jitterMS
+	if t.slowdown > 1 {
+		gap = time.Duration(float64(gap) * t.slowdown)
+	}
+	t.mu.Unlock()
+	if gap <= 0 && jitter <= 0 {
+		return
+	}
+	t.lastMu.Lock()
+	elapsed := time.Since(t.lastReq)
+	t.lastMu.Unlock()
+	d := gap - elapsed
+	if jitter > 0 {
+		d += time.Duration(rand.Intn(jitter+1)) * time.Millisecond
+	}
+	if d > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+	t.lastMu.Lock()
+	t.lastReq = time.Now()
+	t.lastMu.Unlock()
+}
+
+// onTooMany 收到 429 时调用：降速倍数翻倍（不超过 adaptiveMaxSlowdown）；距上次调整不足窗口的 429 视为同一波，忽略。
+func (t *throttle) onTooMany(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := clock()
+	if now.Sub(t.adjustedAt) < adaptiveSlowdownWindow {
+		return
+	}
+	t.limitedAt = now
+	if t.slowdown >= adaptiveMaxSlowdown {
+		return
+	}
+	t.adjustedAt = now
+	t.applySlowdownLocked(min(t.slowdown*adaptiveSlowdownFactor, adaptiveMaxSlowdown))
+	trace.Warn(ctx, "api: 429 自适应降速 x%.2f，当前%s", t.slowdown, t.rateLabelLocked())
+}
+
+// onSuccess 请求成功时调用：降速中且距最近一次 429 与上次调整均满 adaptiveRecoverAfter 时恢复一档。
+func (t *throttle) onSuccess(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := clock()
+	if t.slowdown <= 1 || now.Sub(t.limitedAt) < adaptiveRecoverAfter || now.Sub(t.adjustedAt) < adaptiveRecoverAfter {
+		return
+	}
+	t.adjustedAt = now
+	f := t.slowdown * adaptiveRecoverRatio
+	if f < 1 {
+		f = 1
+	}
+	t.applySlowdownLocked(f)
+	trace.Log(ctx, "api: %s 无 429，恢复速率 x%.2f，当前%s", adaptiveRecoverAfter, t.slowdown, t.rateLabelLocked())
+}
+
+func (t *throttle) applySlowdownLocked(f float64) {
+	t.slowdown = f
+	if t.bucket != nil {
+		t.bucket.setSlowdown(f)
+	}
+}
+
+// rateLabelLocked 当前实际生效的速率描述，用于降速与恢复日志。
+func (t *throttle) rateLabelLocked() string {
+	if t.bucket != nil {
+		return fmt.Sprintf("速率 %.2f rps（配置 %.2f rps）", t.bucket.rate/t.slowdown, t.bucket.rate)
+	}
+	return fmt.Sprintf("请求间隔 %s（配置 %s）", time.Duration(float64(t.gap)*t.slowdown), t.gap)
+}
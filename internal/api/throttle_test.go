@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock 替换包级 clock 为可手动推进的假时钟，测试结束后还原。
+func fakeClock(t *testing.T) *time.Time {
+	t.Helper()
+	now := time.Date(2026, 1, 9, 10, 0, 0, 0, time.UTC)
+	orig := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = orig })
+	return &now
+}
+
+// 429 每秒一次持续到来：每个窗口翻倍一次（x2→x4→x8→x16 封顶），期间成功请求不恢复；停止 30s 后才逐档恢复。
+func TestThrottleAdaptiveSlowdown(t *testing.T) {
+	now := fakeClock(t)
+	ctx := context.Background()
+	th := newThrottle()
+
+	want := []float64{2, 2, 4, 4, 8, 8, 16, 16, 16, 16}
+	for i, w := range want {
+		if i > 0 {
+			*now = now.Add(time.Second)
+		}
+		th.onTooMany(ctx)
+		th.onSuccess(ctx)
+		if th.slowdown != w {
+			t.Fatalf("第 %d 个 429 后 slowdown = %v, want %v", i+1, th.slowdown, w)
+		}
+	}
+	if got, want := th.bucket.slowdown, 16.0; got != want {
+		t.Errorf("令牌桶 slowdown = %v, want %v", got, want)
+	}
+
+	// 429 持续超过 adaptiveRecoverAfter 也不恢复
+	for i := 0; i < 40; i++ {
+		*now = now.Add(time.Second)
+		th.onTooMany(ctx)
+		th.onSuccess(ctx)
+	}
+	if th.slowdown != adaptiveMaxSlowdown {
+		t.Fatalf("429 持续期间 slowdown = %v, want %v", th.slowdown, adaptiveMaxSlowdown)
+	}
+
+	// 停止 429：未满 adaptiveRecoverAfter 不恢复，满后恢复一档，再满一个周期再恢复一档
+	*now = now.Add(adaptiveRecoverAfter - time.Second)
+	th.onSuccess(ctx)
+	if th.slowdown != adaptiveMaxSlowdown {
+		t.Fatalf("最近 429 后 %s slowdown = %v, want %v", adaptiveRecoverAfter-time.Second, th.slowdown, adaptiveMaxSlowdown)
+	}
+	*now = now.Add(time.Second)
+	th.onSuccess(ctx)
+	if want := adaptiveMaxSlowdown * adaptiveRecoverRatio; th.slowdown != want {
+		t.Fatalf("恢复一档后 slowdown = %v, want %v", th.slowdown, want)
+	}
+	th.onSuccess(ctx)
+	if want := adaptiveMaxSlowdown * adaptiveRecoverRatio; th.slowdown != want {
+		t.Fatalf("同一时刻重复成功不应再恢复，slowdown = %v, want %v", th.slowdown, want)
+	}
+	*now = now.Add(adaptiveRecoverAfter)
+	th.onSuccess(ctx)
+	if want := adaptiveMaxSlowdown * adaptiveRecoverRatio * adaptiveRecoverRatio; th.slowdown != want {
+		t.Fatalf("恢复两档后 slowdown = %v, want %v", th.slowdown, want)
+	}
+}
@@ -0,0 +1,152 @@
+// Package backtest 用历史日 K 回放策略：以历史某根 K 线为“当前”切片，复用 worker 的指标计算判断是否入选，
+// 统计入选后持有 N 个交易日的收益，用于量化评估策略而不是盲跑实盘提醒。
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stockMaxWin/internal/api"
+	"stockMaxWin/internal/calendar"
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// 回放参数
+const (
+	klineWorkers    = 4    // 批量拉 K 线的 goroutine 数，实际在途请求仍受 api 限流约束
+	maxKlines       = 1000 // 东方财富单次最多返回的 K 线根数
+	volumeRatioDays = 5    // 历史量比：当日量 / 此前 5 日均量
+	dateLayout      = "2006-01-02"
+)
+
+// ErrStartTooEarly 起始日过早，回放区间加指标预热超过单次可拉取的 K 线根数。
+var ErrStartTooEarly = errors.New("backtest: start date too early")
+
+// BacktestResult 回测汇总：样本为“股票×信号日”，以信号日收盘买入、holdDays 个交易日后收盘卖出。
+type BacktestResult struct {
+	WinRate   float64 // 胜率(%)：持有收益 > 0 的样本占比
+	AvgReturn float64 // 平均收益(%)
+	Count     int     // 样本数
+}
+
+// Run 以当前主板行情为股票池，从 startDate 起逐个交易日回放 strategy，统计入选后持有 holdDays 日的表现。
+// 股票池与市值、PE 等基本面取自当前行情（有幸存者偏差），详见 RunQuotes。
+func Run(ctx context.Context, client *api.Client, strategy filter.Criterion, startDate time.Time, holdDays int) (BacktestResult, error) {
+	quotes, err := client.GetMainBoardQuotes(ctx)
+	if err != nil {
+		return BacktestResult{}, fmt.Errorf("backtest: 拉取股票池: %w", err)
+	}
+	return RunQuotes(ctx, client, quotes, strategy, startDate, holdDays)
+}
+
+// RunQuotes 对给定股票池回放。历史上拿不到的列表字段按 K 线近似：价格、涨跌幅取当日 K 线，
+// 量比为当日量/此前 5 日均量，换手率与市值按当前值随成交量、价格等比缩放；资金流、行业与概念热度置零。
+// 依赖这些字段的条件（如主力净流入）在回测中不会通过。
+func RunQuotes(ctx context.Context, client *api.Client, quotes []model.StockQuote, strategy filter.Criterion, startDate time.Time, holdDays int) (BacktestResult, error) {
+	if holdDays <= 0 {
+		return BacktestResult{}, fmt.Errorf("backtest: holdDays=%d 须大于 0", holdDays)
+	}
+	warmup := worker.KlineCountFor(worker.AllIndicators()...)
+	count := warmup + tradingDaysSince(startDate, time.Now())
+	if count > maxKlines {
+		return BacktestResult{}, fmt.Errorf("%w: 需要 %d 根 K 线，上限 %d", ErrStartTooEarly, count, maxKlines)
+	}
+	codes := make([]string, len(quotes))
+	for i := range quotes {
+		codes[i] = quotes[i].Code
+	}
+	trace.Log(ctx, "backtest: 股票池 %d 只，起始 %s，持有 %d 日，每只拉 %d 根 K 线", len(codes), startDate.Format(dateLayout), holdDays, count)
+	klines, errs := client.GetHisKlinesBatch(ctx, codes, count, klineWorkers)
+	if len(errs) > 0 {
+		trace.Log(ctx, "backtest: %d 只拉取 K 线失败，跳过", len(errs))
+	}
+	if err := ctx.Err(); err != nil {
+		return BacktestResult{}, err
+	}
+
+	start := startDate.Format(dateLayout)
+	var sum float64
+	var n, wins int
+	for i := range quotes {
+		ks := klines[quotes[i].Code]
+		for day := firstIndexFrom(ks, start); day >= 0 && day+holdDays < len(ks); day++ {
+			q := historicalQuote(quotes[i], ks, day)
+			s := worker.Merge(&q, ks[:day+1], false)
+			if s == nil || !strategy(s) || ks[day].Close <= 0 {
+				continue
+			}
+			ret := (ks[day+holdDays].Close/ks[day].Close - 1) * 100
+			sum += ret
+			n++
+			if ret > 0 {
+				wins++
+			}
+		}
+	}
+	res := BacktestResult{Count: n}
+	if n > 0 {
+		res.AvgReturn = sum / float64(n)
+		res.WinRate = float64(wins) * 100 / float64(n)
+	}
+	trace.Log(ctx, "backtest: 样本 %d，平均收益 %.2f%%，胜率 %.1f%%", res.Count, res.AvgReturn, res.WinRate)
+	return res, nil
+}
+
+// tradingDaysSince 统计 [from, to] 内的交易日数。
+func tradingDaysSince(from, to time.Time) int {
+	n := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if calendar.IsTradingDay(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// firstIndexFrom 第一根日期不早于 date 的 K 线下标，没有返回 -1。
+func firstIndexFrom(ks []model.KLine, date string) int {
+	for i := range ks {
+		if ks[i].Date >= date {
+			return i
+		}
+	}
+	return -1
+}
+
+// historicalQuote 以 ks[day] 为当日构造行情，近似方式见 RunQuotes。
+func historicalQuote(cur model.StockQuote, ks []model.KLine, day int) model.StockQuote {
+	k, last := ks[day], ks[len(ks)-1]
+	q := cur
+	q.Price = k.Close
+	q.ChangePct = 0
+	if day > 0 && ks[day-1].Close > 0 {
+		q.ChangePct = (k.Close/ks[day-1].Close - 1) * 100
+	}
+	q.Amount = k.Close * float64(k.Volume) * 100 // 成交量单位为手
+	q.VolumeRatio = 0
+	if day >= volumeRatioDays {
+		var sum int64
+		for _, p := range ks[day-volumeRatioDays : day] {
+			sum += p.Volume
+		}
+		if sum > 0 {
+			q.VolumeRatio = float64(k.Volume) * volumeRatioDays / float64(sum)
+		}
+	}
+	q.TurnoverRate = 0
+	if last.Volume > 0 {
+		q.TurnoverRate = cur.TurnoverRate * float64(k.Volume) / float64(last.Volume)
+	}
+	if last.Close > 0 {
+		q.MarketCap = cur.MarketCap * k.Close / last.Close
+	}
+	q.NetInflow, q.MainForceInflow, q.MainForceOutflow = 0, 0, 0
+	q.IndustryChangePct, q.IndustryRank = 0, 0
+	q.TopConcept, q.TopConceptChangePct = "", 0
+	return q
+}
@@ -0,0 +1,29 @@
+// Package buildinfo 集中管理构建时通过 -ldflags 注入的版本信息，例如：
+//
+//	go build -ldflags "-X stockMaxWin/internal/buildinfo.Version=v1.2.0 -X stockMaxWin/internal/buildinfo.Commit=abc1234 -X stockMaxWin/internal/buildinfo.BuildTime=2026-01-02T15:04:05Z"
+package buildinfo
+
+import "fmt"
+
+// 未注入时的默认值
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info 版本信息，供 /status 等接口序列化。
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+func Get() Info {
+	return Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+}
+
+// String 单行描述，用于启动日志与邮件页脚，如 "v1.2.0 (abc1234, 2026-01-02T15:04:05Z)"。
+func String() string {
+	return fmt.Sprintf("%s (%s, %s)", Version, Commit, BuildTime)
+}
@@ -0,0 +1,113 @@
+// Package calendar 提供 A 股交易日历：周一至周五且不在休市日列表中即为交易日。
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// File holidays.json 格式：holidays 为工作日中的休市日；workdays 为调休补班的周末日期。
+// 补班日单位上班但交易所不开市，仍不是交易日，单独列出只为避免被误当作交易日。
+type File struct {
+	Holidays []string `json:"holidays"`
+	Workdays []string `json:"workdays"`
+}
+
+// Calendar 交易日历，零值为仅按周一至周五判断。
+type Calendar struct {
+	mu       sync.RWMutex
+	holidays map[string]bool
+	workdays map[string]bool
+}
+
+// New 用给定的休市日与补班日构造日历，日期格式 2006-01-02，格式错误时返回错误。
+func New(f File) (*Calendar, error) {
+	c := &Calendar{}
+	if err := c.Merge(f); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Merge 追加休市日与补班日（如从 holidays.json 加载新年度），任一日期无效时不做修改。
+func (c *Calendar) Merge(f File) error {
+	for _, list := range [][]string{f.Holidays, f.Workdays} {
+		for _, d := range list {
+			if _, err := time.Parse(dateLayout, d); err != nil {
+				return fmt.Errorf("calendar: invalid date %q: %w", d, err)
+			}
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.holidays == nil {
+		c.holidays = make(map[string]bool)
+		c.workdays = make(map[string]bool)
+	}
+	for _, d := range f.Holidays {
+		c.holidays[d] = true
+	}
+	for _, d := range f.Workdays {
+		c.workdays[d] = true
+	}
+	return nil
+}
+
+// IsTradingDay t 所在日期（t 的时区）是否为交易日：周末（含调休补班日）与休市日都不是。
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	d := t.Format(dateLayout)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.holidays[d] && !c.workdays[d]
+}
+
+// NextTradingDay 返回 t 之后（不含 t 当天）的第一个交易日，时分秒与 t 相同。
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	next := t
+	for {
+		next = next.AddDate(0, 0, 1)
+		if c.IsTradingDay(next) {
+			return next
+		}
+	}
+}
+
+// LoadFile 读取 holidays.json 并合并到 c；文件不存在返回 os.ErrNotExist 包装的错误。
+func (c *Calendar) LoadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f File
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("calendar: parse %s: %w", path, err)
+	}
+	return c.Merge(f)
+}
+
+var defaultCalendar = mustNew(builtin)
+
+func mustNew(f File) *Calendar {
+	c, err := New(f)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Default 返回内置休市日（可经 LoadFile 追加）的默认日历。
+func Default() *Calendar { return defaultCalendar }
+
+// IsTradingDay 用默认日历判断。
+func IsTradingDay(t time.Time) bool { return defaultCalendar.IsTradingDay(t) }
+
+// NextTradingDay 用默认日历取下一交易日。
+func NextTradingDay(t time.Time) time.Time { return defaultCalendar.NextTradingDay(t) }
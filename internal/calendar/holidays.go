@@ -0,0 +1,29 @@
+package calendar
+
+// builtin 内置沪深交易所休市安排（仅列工作日中的休市日与周末补班日），以交易所年度公告为准；
+// 新年度公告发布后可在 holidays.json 中追加，无需重新编译。
+var builtin = File{
+	Holidays: []string{
+		// 2025
+		"2025-01-01",
+		"2025-01-28", "2025-01-29", "2025-01-30", "2025-01-31", "2025-02-03", "2025-02-04",
+		"2025-04-04",
+		"2025-05-01", "2025-05-02", "2025-05-05",
+		"2025-06-02",
+		"2025-10-01", "2025-10-02", "2025-10-03", "2025-10-06", "2025-10-07", "2025-10-08",
+		// 2026
+		"2026-01-01", "2026-01-02",
+		"2026-02-16", "2026-02-17", "2026-02-18", "2026-02-19", "2026-02-20", "2026-02-23",
+		"2026-04-06",
+		"2026-05-01", "2026-05-04", "2026-05-05",
+		"2026-06-19",
+		"2026-09-25",
+		"2026-10-01", "2026-10-02", "2026-10-05", "2026-10-06", "2026-10-07",
+	},
+	Workdays: []string{
+		// 2025
+		"2025-01-26", "2025-02-08", "2025-04-27", "2025-09-28", "2025-10-11",
+		// 2026
+		"2026-01-04", "2026-02-14", "2026-02-28", "2026-05-09", "2026-09-20", "2026-10-10",
+	},
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+)
+
+// envStrategyJSON 直接传入完整策略 JSON（容器/k8s 用 env 或 secret 配置），优先于配置文件
+const envStrategyJSON = "STOCKMAXWIN_STRATEGY_JSON"
+
+// criteriaFile 策略定义中的条件段："criteria": {"turnover_range": [3, 10], "exclude_st": []}
+type criteriaFile struct {
+	Criteria map[string][]float64 `json:"criteria"`
+}
+
+// LoadCriteria 读取按名引用的选股条件：先看 STOCKMAXWIN_STRATEGY_JSON，其次配置文件；都未配置时返回 nil（使用内置策略）。
+// 环境变量既可写 {"criteria": {...}}，也可直接写条件对象 {"turnover_range": [3, 10]}；
+// 只含阈值字段（如 {"turnover_min": 3}）时视为未配置条件，返回 nil 且不再读配置文件，以免文件中的条件覆盖环境变量的本意。
+func LoadCriteria() map[string][]float64 {
+	if s := strings.TrimSpace(os.Getenv(envStrategyJSON)); s != "" {
+		c, err := parseCriteriaJSON([]byte(s))
+		if err == nil {
+			return c
+		}
+		log.Printf("config: %s 解析失败，改读配置文件: %v", envStrategyJSON, err)
+	}
+	var f criteriaFile
+	readConfigFile(&f)
+	if len(f.Criteria) == 0 {
+		return nil
+	}
+	return f.Criteria
+}
+
+// parseCriteriaJSON 解析环境变量中的策略 JSON：有 criteria 键时取其值；否则每个值都是数组时按裸条件对象解析，
+// 其余（阈值字段等）视为没有条件，返回 nil。只有 JSON 本身无效或 criteria 段格式错误才返回错误。
+func parseCriteriaJSON(b []byte) (map[string][]float64, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	if _, ok := raw["criteria"]; ok {
+		var f criteriaFile
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, err
+		}
+		if len(f.Criteria) == 0 {
+			return nil, nil
+		}
+		return f.Criteria, nil
+	}
+	m := make(map[string][]float64, len(raw))
+	for name, v := range raw {
+		var args []float64
+		if err := json.Unmarshal(v, &args); err != nil {
+			return nil, nil
+		}
+		m[name] = args
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
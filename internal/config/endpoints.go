@@ -0,0 +1,33 @@
+package config
+
+import "os"
+
+// 行情接口地址覆盖环境变量：可填完整 URL 或仅 host，便于切换东方财富节点或指向自建镜像/缓存代理
+const (
+	envAPIListURL  = "STOCKMAXWIN_API_LIST_URL"
+	envAPIKLineURL = "STOCKMAXWIN_API_KLINE_URL"
+	envAPIIndexURL = "STOCKMAXWIN_API_INDEX_URL"
+)
+
+// APIEndpoints 行情接口地址，空字段表示用默认地址。
+type APIEndpoints struct {
+	ListURL  string `json:"api_list_url"`
+	KLineURL string `json:"api_kline_url"`
+	IndexURL string `json:"api_index_url"`
+}
+
+// LoadAPIEndpoints 先读配置文件，再被环境变量覆盖。
+func LoadAPIEndpoints() *APIEndpoints {
+	cfg := &APIEndpoints{}
+	readConfigFile(cfg)
+	if v := os.Getenv(envAPIListURL); v != "" {
+		cfg.ListURL = v
+	}
+	if v := os.Getenv(envAPIKLineURL); v != "" {
+		cfg.KLineURL = v
+	}
+	if v := os.Getenv(envAPIIndexURL); v != "" {
+		cfg.IndexURL = v
+	}
+	return cfg
+}
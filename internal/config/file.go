@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFilePath 返回配置文件路径：envConfigPath 优先，默认 config.json。
+func configFilePath() string {
+	if p := os.Getenv(envConfigPath); p != "" {
+		return p
+	}
+	return defaultConfigPath
+}
+
+// readConfigFile 读取配置文件并解析到 v；文件不存在或解析失败时保持 v 不变。
+func readConfigFile(v interface{}) {
+	path := configFilePath()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	_ = decodeConfig(path, b, v)
+}
+
+// decodeConfig 按扩展名解析配置：.yaml/.yml 为 YAML，其余按 JSON。
+// YAML 先解码为通用结构再转成 JSON 解析，字段名沿用结构体的 json tag，两种格式写法一致、无需重复声明 yaml tag。
+func decodeConfig(path string, b []byte, v interface{}) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(b, &raw); err != nil {
+			return fmt.Errorf("yaml: %w", err)
+		}
+		if raw == nil {
+			return nil
+		}
+		jb, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("yaml: %w", err)
+		}
+		b = jb
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Ollama 点评环境变量：服务地址（默认 http://localhost:11434）与模型名（配置模型名即启用）
+const (
+	envOllamaEndpoint = "STOCKMAXWIN_OLLAMA_ENDPOINT"
+	envOllamaModel    = "STOCKMAXWIN_OLLAMA_MODEL"
+)
+
+// LLM 可选的大模型点评配置。
+type LLM struct {
+	OllamaEndpoint string `json:"ollama_endpoint"`
+	OllamaModel    string `json:"ollama_model"`
+}
+
+// LoadLLM 先读配置文件，再被环境变量覆盖。
+func LoadLLM() *LLM {
+	cfg := &LLM{}
+	readConfigFile(cfg)
+	if v := os.Getenv(envOllamaEndpoint); v != "" {
+		cfg.OllamaEndpoint = v
+	}
+	if v := os.Getenv(envOllamaModel); v != "" {
+		cfg.OllamaModel = v
+	}
+	return cfg
+}
+
+func (l *LLM) Enabled() bool {
+	return strings.TrimSpace(l.OllamaModel) != ""
+}
@@ -0,0 +1,26 @@
+package config
+
+import "os"
+
+// envMailTheme 邮件主题名：light（默认）或 dark
+const envMailTheme = "STOCKMAXWIN_MAIL_THEME"
+
+// MailTheme 邮件主题：内置主题名，以及可选的单项颜色/字体覆盖（留空沿用内置主题）。
+type MailTheme struct {
+	Name       string `json:"mail_theme"`
+	Primary    string `json:"mail_theme_primary"`
+	Up         string `json:"mail_theme_up"`
+	Down       string `json:"mail_theme_down"`
+	Background string `json:"mail_theme_background"`
+	Font       string `json:"mail_theme_font"`
+}
+
+// LoadMailTheme 先读配置文件，主题名可被环境变量覆盖。
+func LoadMailTheme() *MailTheme {
+	cfg := &MailTheme{}
+	readConfigFile(cfg)
+	if v := os.Getenv(envMailTheme); v != "" {
+		cfg.Name = v
+	}
+	return cfg
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 企业微信应用消息环境变量名
+const (
+	envWeComCorpID     = "WECOM_CORP_ID"
+	envWeComCorpSecret = "WECOM_CORP_SECRET"
+	envWeComAgentID    = "WECOM_AGENT_ID"
+	envWeComToUser     = "WECOM_TO_USER"
+	envWeComMsgType    = "WECOM_MSG_TYPE"
+)
+
+// WeComApp 企业微信应用消息配置（非群机器人）：corpid/corpsecret/agentid，发给 touser（多个用 | 分隔）。
+type WeComApp struct {
+	CorpID     string `json:"wecom_corp_id"`
+	CorpSecret string `json:"wecom_corp_secret"`
+	AgentID    int    `json:"wecom_agent_id"`
+	ToUser     string `json:"wecom_to_user"`
+	MsgType    string `json:"wecom_msg_type"`
+}
+
+// LoadWeComApp 先读配置文件，再被环境变量覆盖。
+func LoadWeComApp() *WeComApp {
+	cfg := &WeComApp{}
+	readConfigFile(cfg)
+	if v := os.Getenv(envWeComCorpID); v != "" {
+		cfg.CorpID = v
+	}
+	if v := os.Getenv(envWeComCorpSecret); v != "" {
+		cfg.CorpSecret = v
+	}
+	if v := os.Getenv(envWeComAgentID); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.AgentID = n
+		}
+	}
+	if v := os.Getenv(envWeComToUser); v != "" {
+		cfg.ToUser = v
+	}
+	if v := os.Getenv(envWeComMsgType); v != "" {
+		cfg.MsgType = v
+	}
+	return cfg
+}
+
+func (w *WeComApp) Enabled() bool {
+	return strings.TrimSpace(w.CorpID) != "" && strings.TrimSpace(w.CorpSecret) != "" && w.AgentID > 0
+}
+
+// 群机器人 Webhook 环境变量名：飞书、钉钉可配加签密钥，企业微信群机器人只需地址
+const (
+	envFeishuWebhook   = "FEISHU_WEBHOOK_URL"
+	envFeishuSecret    = "FEISHU_SECRET"
+	envDingTalkWebhook = "DINGTALK_WEBHOOK_URL"
+	envDingTalkSecret  = "DINGTALK_SECRET"
+	envWeComWebhook    = "WECOM_WEBHOOK_URL"
+)
+
+// Webhooks 群机器人推送配置，地址为空的渠道不启用，可同时启用多个。
+type Webhooks struct {
+	FeishuURL      string `json:"feishu_webhook_url"`
+	FeishuSecret   string `json:"feishu_secret"`
+	DingTalkURL    string `json:"dingtalk_webhook_url"`
+	DingTalkSecret string `json:"dingtalk_secret"`
+	WeComURL       string `json:"wecom_webhook_url"`
+}
+
+// LoadWebhooks 先读配置文件，再被环境变量覆盖。
+func LoadWebhooks() *Webhooks {
+	cfg := &Webhooks{}
+	readConfigFile(cfg)
+	for _, f := range []struct {
+		env string
+		dst *string
+	}{
+		{envFeishuWebhook, &cfg.FeishuURL},
+		{envFeishuSecret, &cfg.FeishuSecret},
+		{envDingTalkWebhook, &cfg.DingTalkURL},
+		{envDingTalkSecret, &cfg.DingTalkSecret},
+		{envWeComWebhook, &cfg.WeComURL},
+	} {
+		if v := os.Getenv(f.env); v != "" {
+			*f.dst = v
+		}
+	}
+	return cfg
+}
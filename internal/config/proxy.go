@@ -0,0 +1,33 @@
+package config
+
+import "os"
+
+// 代理环境变量：代理地址、走代理的域名列表、直连域名列表（逗号分隔，均支持子域名）
+const (
+	envProxy        = "STOCKMAXWIN_PROXY"
+	envProxyDomains = "STOCKMAXWIN_PROXY_DOMAINS"
+	envNoProxy      = "STOCKMAXWIN_NO_PROXY"
+)
+
+// Proxy 代理配置：URL 为空时使用系统环境变量 HTTP_PROXY/HTTPS_PROXY；Domains 为空时除 NoProxy 外全部走代理。
+type Proxy struct {
+	URL     string `json:"proxy_url"`
+	Domains string `json:"proxy_domains"`
+	NoProxy string `json:"no_proxy"`
+}
+
+// LoadProxy 先读配置文件，再被环境变量覆盖。
+func LoadProxy() *Proxy {
+	cfg := &Proxy{}
+	readConfigFile(cfg)
+	if v := os.Getenv(envProxy); v != "" {
+		cfg.URL = v
+	}
+	if v := os.Getenv(envProxyDomains); v != "" {
+		cfg.Domains = v
+	}
+	if v := os.Getenv(envNoProxy); v != "" {
+		cfg.NoProxy = v
+	}
+	return cfg
+}
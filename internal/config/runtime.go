@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 运行参数环境变量名（与 main / api 包原有变量一致）
+const (
+	envConcurrency      = "STOCKMAXWIN_CONCURRENCY"
+	envAPIDelayMS       = "STOCKMAXWIN_API_DELAY_MS"
+	envAPIJitterMS      = "STOCKMAXWIN_API_JITTER_MS"
+	envAPIMaxConcurrent = "STOCKMAXWIN_API_MAX_CONCURRENT"
+	envKlineTTLMinutes  = "STOCKMAXWIN_KLINE_CACHE_TTL_MINUTES"
+	envAPIRPS           = "STOCKMAXWIN_API_RPS"
+)
+
+// Runtime 可在运行中重载的参数：worker 并发、API 限流与 K 线内存缓存有效期。0 / 负数表示未配置，由使用方回退默认值；
+// KlineTTLMinutes、APIRPS 例外：负数为未配置，0 分别表示关闭缓存、回退固定间隔节流。
+type Runtime struct {
+	Concurrency      int     `json:"concurrency"`
+	APIDelayMS       int     `json:"api_delay_ms"`
+	APIJitterMS      int     `json:"api_jitter_ms"`
+	APIMaxConcurrent int     `json:"api_max_concurrent"`
+	KlineTTLMinutes  int     `json:"kline_cache_ttl_minutes"`
+	APIRPS           float64 `json:"api_rps"`
+	APIBurst         int     `json:"api_burst"`
+}
+
+// LoadRuntime 先读配置文件，再被环境变量覆盖；每次调用都重新读取，供 SIGHUP 重载使用。
+func LoadRuntime() *Runtime {
+	cfg := &Runtime{APIJitterMS: -1, KlineTTLMinutes: -1, APIRPS: -1}
+	readConfigFile(cfg)
+	if n, ok := envInt(envConcurrency); ok && n > 0 {
+		cfg.Concurrency = n
+	}
+	if n, ok := envInt(envAPIDelayMS); ok && n > 0 {
+		cfg.APIDelayMS = n
+	}
+	if n, ok := envInt(envAPIJitterMS); ok && n >= 0 {
+		cfg.APIJitterMS = n
+	}
+	if n, ok := envInt(envAPIMaxConcurrent); ok && n > 0 {
+		cfg.APIMaxConcurrent = n
+	}
+	if n, ok := envInt(envKlineTTLMinutes); ok && n >= 0 {
+		cfg.KlineTTLMinutes = n
+	}
+	if rps, burst, ok := envRPS(envAPIRPS); ok {
+		cfg.APIRPS, cfg.APIBurst = rps, burst
+	}
+	return cfg
+}
+
+func envInt(name string) (int, bool) {
+	s := os.Getenv(name)
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// envRPS 解析 "速率" 或 "速率,突发"，突发缺省为 0（由使用方取默认值）。
+func envRPS(name string) (rps float64, burst int, ok bool) {
+	rateStr, burstStr, hasBurst := strings.Cut(os.Getenv(name), ",")
+	rps, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil || rps < 0 {
+		return 0, 0, false
+	}
+	if hasBurst {
+		if burst, err = strconv.Atoi(strings.TrimSpace(burstStr)); err != nil || burst < 1 {
+			return 0, 0, false
+		}
+	}
+	return rps, burst, true
+}
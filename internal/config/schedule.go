@@ -0,0 +1,40 @@
+package config
+
+// 调度时间段环境变量，优先于配置文件
+const (
+	envScheduleOpenHour    = "STOCKMAXWIN_SCHEDULE_OPEN_HOUR"
+	envScheduleOpenMinute  = "STOCKMAXWIN_SCHEDULE_OPEN_MINUTE"
+	envScheduleCloseHour   = "STOCKMAXWIN_SCHEDULE_CLOSE_HOUR"
+	envScheduleCloseMinute = "STOCKMAXWIN_SCHEDULE_CLOSE_MINUTE"
+	envScheduleInterval    = "STOCKMAXWIN_SCHEDULE_INTERVAL_MINUTES"
+)
+
+// Schedule 调度时间段（本地时区）：开盘首个执行点、收盘执行点与间隔分钟。-1 表示未配置，由使用方回退默认（A 股 9:15~15:00 每 30 分钟）。
+type Schedule struct {
+	OpenHour        int `json:"schedule_open_hour"`
+	OpenMinute      int `json:"schedule_open_minute"`
+	CloseHour       int `json:"schedule_close_hour"`
+	CloseMinute     int `json:"schedule_close_minute"`
+	IntervalMinutes int `json:"schedule_interval_minutes"`
+}
+
+// LoadSchedule 先读配置文件，再被环境变量覆盖，未配置的字段为 -1；取值是否合法由使用方校验。
+func LoadSchedule() *Schedule {
+	cfg := &Schedule{OpenHour: -1, OpenMinute: -1, CloseHour: -1, CloseMinute: -1, IntervalMinutes: -1}
+	readConfigFile(cfg)
+	for _, f := range []struct {
+		env string
+		dst *int
+	}{
+		{envScheduleOpenHour, &cfg.OpenHour},
+		{envScheduleOpenMinute, &cfg.OpenMinute},
+		{envScheduleCloseHour, &cfg.CloseHour},
+		{envScheduleCloseMinute, &cfg.CloseMinute},
+		{envScheduleInterval, &cfg.IntervalMinutes},
+	} {
+		if n, ok := envInt(f.env); ok {
+			*f.dst = n
+		}
+	}
+	return cfg
+}
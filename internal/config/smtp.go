@@ -2,7 +2,6 @@
 package config
 
 import (
-	"encoding/json"
 	"os"
 	"strconv"
 	"strings"
@@ -28,18 +27,23 @@ type SMTP struct {
 	Password string `json:"smtp_password"`
 	From     string `json:"smtp_from"`
 	To       string `json:"smtp_to"`
+	// Servers 备用 SMTP 列表（配置文件 smtp_servers），按顺序在主服务器失败后尝试；未配 smtp_server 时第一个即为主服务器
+	Servers []SMTPServer `json:"smtp_servers"`
+}
+
+// SMTPServer smtp_servers 中的一项，字段名与单服务器配置相同；收件人统一用 smtp_to，smtp_from 为空时取 smtp_user。
+type SMTPServer struct {
+	Server   string `json:"smtp_server"`
+	Port     int    `json:"smtp_port"`
+	User     string `json:"smtp_user"`
+	Password string `json:"smtp_password"`
+	From     string `json:"smtp_from"`
 }
 
 // LoadSMTP 先读 envConfigPath 指定文件（默认 config.json），再被环境变量覆盖。
 func LoadSMTP() *SMTP {
 	cfg := &SMTP{}
-	configPath := os.Getenv(envConfigPath)
-	if configPath == "" {
-		configPath = defaultConfigPath
-	}
-	if b, err := os.ReadFile(configPath); err == nil {
-		_ = json.Unmarshal(b, cfg)
-	}
+	readConfigFile(cfg)
 	if v := os.Getenv(envSMTPServer); v != "" {
 		cfg.Server = v
 	}
@@ -67,12 +71,25 @@ func LoadSMTP() *SMTP {
 	if cfg.From == "" && cfg.User != "" {
 		cfg.From = cfg.User
 	}
+	for i := range cfg.Servers {
+		if cfg.Servers[i].From == "" {
+			cfg.Servers[i].From = cfg.Servers[i].User
+		}
+	}
+	if cfg.From == "" && len(cfg.Servers) > 0 {
+		cfg.From = cfg.Servers[0].From
+	}
 
 	return cfg
 }
 
 func (s *SMTP) Enabled() bool {
 	srv := strings.TrimSpace(s.Server)
+	for _, b := range s.Servers {
+		if srv == "" {
+			srv = strings.TrimSpace(b.Server)
+		}
+	}
 	from := strings.TrimSpace(s.From)
 	to := strings.TrimSpace(s.To)
 	return srv != "" && from != "" && to != ""
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// 策略阈值文件：STOCKMAXWIN_STRATEGY_FILE 指定路径，默认 strategy.json（不存在时全部用内置默认值）
+const (
+	envStrategyFile     = "STOCKMAXWIN_STRATEGY_FILE"
+	defaultStrategyFile = "strategy.json"
+)
+
+// StrategyConfig 趋势动能策略阈值，未配置的字段为 nil，由使用方用内置默认值兜底。
+// 市值单位为元，涨幅/换手单位为 %；上限类字段配置为 0 表示不设上限。
+type StrategyConfig struct {
+	MarketCapMin   *float64 `json:"market_cap_min"`
+	MarketCapMax   *float64 `json:"market_cap_max"`
+	PEMin          *float64 `json:"pe_min"`
+	PEMax          *float64 `json:"pe_max"`
+	TurnoverMin    *float64 `json:"turnover_min"`
+	TurnoverMax    *float64 `json:"turnover_max"`
+	VolumeRatioMin *float64 `json:"volume_ratio_min"`
+	ChangePctMin   *float64 `json:"change_pct_min"`
+	ChangePctMax   *float64 `json:"change_pct_max"`
+	AmountMin      *float64 `json:"amount_min"`
+	NetInflowMin   *float64 `json:"net_inflow_min"`
+}
+
+func strategyFilePath() string {
+	if p := os.Getenv(envStrategyFile); p != "" {
+		return p
+	}
+	return defaultStrategyFile
+}
+
+// LoadStrategy 读取策略阈值：与 LoadCriteria 同样先看 STOCKMAXWIN_STRATEGY_JSON（同一份 JSON 中的阈值字段，
+// 如 {"turnover_min": 3, "criteria": {...}}），解析失败时记日志改读文件；其次读策略阈值文件（.yaml/.yml 按 YAML，否则 JSON）。
+// 文件不存在返回空配置，解析失败返回空配置与错误（调用方记日志后用默认值）。
+func LoadStrategy() (*StrategyConfig, error) {
+	if s := strings.TrimSpace(os.Getenv(envStrategyJSON)); s != "" {
+		cfg := &StrategyConfig{}
+		err := json.Unmarshal([]byte(s), cfg)
+		if err == nil {
+			return cfg, nil
+		}
+		log.Printf("config: %s 解析失败，改读策略阈值文件: %v", envStrategyJSON, err)
+	}
+	cfg := &StrategyConfig{}
+	path := strategyFilePath()
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := decodeConfig(path, b, cfg); err != nil {
+		return &StrategyConfig{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
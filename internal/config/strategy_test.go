@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadStrategyEnvFirst STOCKMAXWIN_STRATEGY_JSON 优先于策略阈值文件，与 LoadCriteria 的优先级一致；无效时回退文件。
+func TestLoadStrategyEnvFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategy.json")
+	if err := os.WriteFile(path, []byte(`{"turnover_min": 5}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envStrategyFile, path)
+
+	tests := []struct {
+		name string
+		env  string
+		want float64
+	}{
+		{"未设置环境变量读文件", "", 5},
+		{"环境变量优先", `{"turnover_min": 2, "criteria": {"exclude_st": []}}`, 2},
+		{"环境变量无效回退文件", `{not json`, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envStrategyJSON, tt.env)
+			cfg, err := LoadStrategy()
+			if err != nil {
+				t.Fatalf("LoadStrategy() err = %v", err)
+			}
+			if cfg.TurnoverMin == nil || *cfg.TurnoverMin != tt.want {
+				t.Errorf("TurnoverMin = %v, want %v", cfg.TurnoverMin, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadCriteriaEnv STOCKMAXWIN_STRATEGY_JSON 只含阈值时视为未配置条件：返回 nil，不回退读配置文件中的条件。
+func TestLoadCriteriaEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"criteria": {"exclude_st": []}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envConfigPath, path)
+
+	tests := []struct {
+		name string
+		env  string
+		want map[string][]float64
+	}{
+		{"未设置环境变量读文件", "", map[string][]float64{"exclude_st": {}}},
+		{"criteria 段", `{"turnover_min": 2, "criteria": {"turnover_range": [3, 10]}}`, map[string][]float64{"turnover_range": {3, 10}}},
+		{"裸条件对象", `{"turnover_range": [3, 10], "exclude_st": []}`, map[string][]float64{"turnover_range": {3, 10}, "exclude_st": {}}},
+		{"只含阈值不读文件", `{"turnover_min": 3}`, nil},
+		{"空 criteria 不读文件", `{"turnover_min": 3, "criteria": {}}`, nil},
+		{"JSON 无效回退文件", `{not json`, map[string][]float64{"exclude_st": {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envStrategyJSON, tt.env)
+			got := LoadCriteria()
+			if len(got) != len(tt.want) {
+				t.Fatalf("LoadCriteria() = %v, want %v", got, tt.want)
+			}
+			for name, args := range tt.want {
+				g, ok := got[name]
+				if !ok || len(g) != len(args) {
+					t.Fatalf("LoadCriteria() = %v, want %v", got, tt.want)
+				}
+				for i := range args {
+					if g[i] != args[i] {
+						t.Errorf("%s[%d] = %v, want %v", name, i, g[i], args[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 初选强度分环境变量：
+// STOCKMAXWIN_STRENGTH_WEIGHTS="量比,换手,涨幅" 权重（如 0.4,0.3,0.3）；
+// STOCKMAXWIN_CANDIDATE_TOP=n 初选后按强度分取前 n 只再拉 K 线，0 或未配置不截断。
+const (
+	envStrengthWeights = "STOCKMAXWIN_STRENGTH_WEIGHTS"
+	envCandidateTop    = "STOCKMAXWIN_CANDIDATE_TOP"
+)
+
+// Strength 初选强度分配置。权重为负数表示未配置，由使用方回退默认。
+type Strength struct {
+	VolumeRatioWeight  float64 `json:"strength_volume_ratio_weight"`
+	TurnoverRateWeight float64 `json:"strength_turnover_rate_weight"`
+	ChangePctWeight    float64 `json:"strength_change_pct_weight"`
+	CandidateTop       int     `json:"candidate_top"`
+}
+
+// LoadStrength 先读配置文件，再被环境变量覆盖。
+func LoadStrength() *Strength {
+	cfg := &Strength{VolumeRatioWeight: -1, TurnoverRateWeight: -1, ChangePctWeight: -1}
+	readConfigFile(cfg)
+	if s := strings.TrimSpace(os.Getenv(envStrengthWeights)); s != "" {
+		if w, ok := parseWeights(s, 3); ok {
+			cfg.VolumeRatioWeight, cfg.TurnoverRateWeight, cfg.ChangePctWeight = w[0], w[1], w[2]
+		} else {
+			log.Printf("config: %s=%q 格式应为 \"量比,换手,涨幅\"，忽略", envStrengthWeights, s)
+		}
+	}
+	if n, ok := envInt(envCandidateTop); ok {
+		cfg.CandidateTop = n
+	}
+	return cfg
+}
+
+// WeightsSet 三项权重都已配置（非负）且不全为 0。
+func (s *Strength) WeightsSet() bool {
+	if s.VolumeRatioWeight < 0 || s.TurnoverRateWeight < 0 || s.ChangePctWeight < 0 {
+		return false
+	}
+	return s.VolumeRatioWeight+s.TurnoverRateWeight+s.ChangePctWeight > 0
+}
+
+func parseWeights(s string, n int) ([]float64, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+	out := make([]float64, n)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || v < 0 {
+			return nil, false
+		}
+		out[i] = v
+	}
+	return out, true
+}
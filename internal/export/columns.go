@@ -0,0 +1,132 @@
+// Package export 把选股结果序列化为 JSON / CSV，列由 Selector（字段白名单）决定，邮件表格共用同一套列定义。
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"stockMaxWin/internal/model"
+)
+
+// 市值展示单位：亿元
+const yi = 1e8
+
+// Column 可导出的一列：Key 用于配置白名单与 JSON 字段名，Header 为表头。
+type Column struct {
+	Key    string
+	Header string
+	value  func(*model.Stock) interface{}
+	format string // 文本格式，空为 %v
+}
+
+// Value 返回原始值（JSON 使用）。
+func (c Column) Value(s *model.Stock) interface{} { return c.value(s) }
+
+// Text 返回格式化文本（CSV、邮件使用）。
+func (c Column) Text(s *model.Stock) string {
+	if c.format == "" {
+		return fmt.Sprintf("%v", c.value(s))
+	}
+	return fmt.Sprintf(c.format, c.value(s))
+}
+
+func str(f func(*model.Stock) string) func(*model.Stock) interface{} {
+	return func(s *model.Stock) interface{} { return f(s) }
+}
+
+func num(f func(*model.Stock) float64) func(*model.Stock) interface{} {
+	return func(s *model.Stock) interface{} { return f(s) }
+}
+
+// allColumns 全部可选列，顺序即默认输出顺序。
+var allColumns = []Column{
+	{Key: "code", Header: "代码", value: str(func(s *model.Stock) string { return s.Code })},
+	{Key: "name", Header: "名称", value: str(func(s *model.Stock) string { return s.Name })},
+	{Key: "industry", Header: "行业", value: str(func(s *model.Stock) string { return s.Industry })},
+	{Key: "top_concept", Header: "最强概念", value: str(topConcept)},
+	{Key: "main_business", Header: "主营领域", value: str(func(s *model.Stock) string { return s.MainBusiness })},
+	{Key: "price", Header: "现价", value: num(func(s *model.Stock) float64 { return s.Price }), format: "%.2f"},
+	{Key: "change_pct", Header: "涨幅%", value: num(func(s *model.Stock) float64 { return s.ChangePct }), format: "%.2f"},
+	{Key: "ma5", Header: "MA5", value: num(func(s *model.Stock) float64 { return s.MA5 }), format: "%.2f"},
+	{Key: "ma10", Header: "MA10", value: num(func(s *model.Stock) float64 { return s.MA10 }), format: "%.2f"},
+	{Key: "ma20", Header: "MA20", value: num(func(s *model.Stock) float64 { return s.MA20 }), format: "%.2f"},
+	{Key: "ma60", Header: "MA60", value: num(func(s *model.Stock) float64 { return s.MA60 }), format: "%.2f"},
+	{Key: "macd_histogram", Header: "MACD红柱", value: num(func(s *model.Stock) float64 { return s.MacdHistogram }), format: "%.3f"},
+	{Key: "turnover_rate", Header: "换手%", value: num(func(s *model.Stock) float64 { return s.TurnoverRate }), format: "%.2f"},
+	{Key: "volume_ratio", Header: "量比", value: num(func(s *model.Stock) float64 { return s.VolumeRatio }), format: "%.2f"},
+	{Key: "amount", Header: "成交额", value: num(func(s *model.Stock) float64 { return s.Amount }), format: "%.0f"},
+	{Key: "market_cap", Header: "市值(亿)", value: num(func(s *model.Stock) float64 { return s.MarketCap / yi }), format: "%.2f"},
+	{Key: "pe", Header: "PE", value: num(func(s *model.Stock) float64 { return s.PE }), format: "%.2f"},
+	{Key: "pb", Header: "PB", value: num(func(s *model.Stock) float64 { return s.PB }), format: "%.2f"},
+	{Key: "roe", Header: "ROE%", value: num(func(s *model.Stock) float64 { return s.ROE }), format: "%.2f"},
+	{Key: "net_inflow", Header: "主力净流入", value: num(func(s *model.Stock) float64 { return s.NetInflow }), format: "%.0f"},
+	{Key: "main_force_inflow", Header: "主力流入", value: num(func(s *model.Stock) float64 { return s.MainForceInflow }), format: "%.0f"},
+	{Key: "main_force_outflow", Header: "主力流出", value: num(func(s *model.Stock) float64 { return s.MainForceOutflow }), format: "%.0f"},
+	{Key: "score", Header: "评分", value: num(func(s *model.Stock) float64 { return s.Score }), format: "%.1f"},
+	{Key: "prefilter_rank", Header: "初选排名", value: func(s *model.Stock) interface{} { return s.PreFilterRank }},
+}
+
+// topConcept 最强概念及其当日涨幅，如 "算力 +3.25%"；无概念数据时为空。
+func topConcept(s *model.Stock) string {
+	if s.TopConcept == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %+.2f%%", s.TopConcept, s.TopConceptChangePct)
+}
+
+// Selector 按白名单选出的列，顺序与配置一致。
+type Selector []Column
+
+// AllColumns 返回全部可选列的 Key，便于文档与校验。
+func AllColumns() []string {
+	keys := make([]string, len(allColumns))
+	for i, c := range allColumns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// NewSelector 按字段白名单构造列选择器，未知字段忽略；白名单为空（或全部未知）时返回 def 对应的列，def 也为空则全部列。
+func NewSelector(fields []string, def ...string) Selector {
+	sel := pick(fields)
+	if len(sel) == 0 {
+		sel = pick(def)
+	}
+	if len(sel) == 0 {
+		sel = append(Selector(nil), allColumns...)
+	}
+	return sel
+}
+
+// ParseFields 解析逗号分隔的字段列表，如 "code,name,change_pct"。
+func ParseFields(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func pick(fields []string) Selector {
+	var sel Selector
+	for _, f := range fields {
+		for _, c := range allColumns {
+			if c.Key == f {
+				sel = append(sel, c)
+				break
+			}
+		}
+	}
+	return sel
+}
+
+// Headers 返回表头。
+func (sel Selector) Headers() []string {
+	out := make([]string, len(sel))
+	for i, c := range sel {
+		out[i] = c.Header
+	}
+	return out
+}
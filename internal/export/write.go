@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"stockMaxWin/internal/model"
+)
+
+// WriteJSON 输出 JSON 数组，每只股票一个对象，仅含选中的列。
+func (sel Selector) WriteJSON(w io.Writer, stocks []*model.Stock) error {
+	rows := make([]map[string]interface{}, 0, len(stocks))
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		row := make(map[string]interface{}, len(sel))
+		for _, c := range sel {
+			row[c.Key] = c.Value(s)
+		}
+		rows = append(rows, row)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+// WriteCSV 输出带表头的 CSV，仅含选中的列；逗号、引号由 encoding/csv 转义。
+func (sel Selector) WriteCSV(w io.Writer, stocks []*model.Stock) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sel.Headers()); err != nil {
+		return err
+	}
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		rec := make([]string, len(sel))
+		for i, c := range sel {
+			rec[i] = c.Text(s)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvColumns WriteCSV 的固定列：代码、名称、现价、涨跌幅、MA20、MA60、MACD红柱、换手、量比、市值、PE。
+var csvColumns = []string{
+	"code", "name", "price", "change_pct", "ma20", "ma60", "macd_histogram", "turnover_rate", "volume_ratio", "market_cap", "pe",
+}
+
+// WriteCSV 按固定列输出选股结果 CSV，供留档与回测；需要自定义列时用 Selector.WriteCSV。
+func WriteCSV(w io.Writer, stocks []*model.Stock) error {
+	return NewSelector(csvColumns).WriteCSV(w, stocks)
+}
@@ -0,0 +1,96 @@
+// Package feed 把选股结果输出为 Atom feed，供 RSS 阅读器订阅。
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+// Entry 一轮选股对应一个 entry，HTML 为股票列表。
+type Entry struct {
+	ID      string
+	Title   string
+	Updated time.Time
+	HTML    string
+}
+
+// Feed Atom feed 元信息，Link 为订阅地址（可空）。
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string
+	Author  string
+	Entries []Entry
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	NS      string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// WriteAtom 输出 Atom XML；feed 的 updated 取最新 entry 时间，无 entry 时为当前时间。
+func (f Feed) WriteAtom(w io.Writer) error {
+	updated := time.Now()
+	if len(f.Entries) > 0 {
+		updated = f.Entries[0].Updated
+		for _, e := range f.Entries {
+			if e.Updated.After(updated) {
+				updated = e.Updated
+			}
+		}
+	}
+	out := atomFeed{
+		NS:      atomNS,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: updated.Format(time.RFC3339),
+		Author:  atomAuthor{Name: f.Author},
+	}
+	if f.Link != "" {
+		out.Link = &atomLink{Href: f.Link, Rel: "self"}
+	}
+	for _, e := range f.Entries {
+		out.Entries = append(out.Entries, atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Updated: e.Updated.Format(time.RFC3339),
+			Content: atomContent{Type: "html", Body: e.HTML},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(out)
+}
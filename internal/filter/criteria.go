@@ -2,6 +2,7 @@
 package filter
 
 import (
+	"sort"
 	"strings"
 
 	"stockMaxWin/internal/model"
@@ -13,14 +14,6 @@ const (
 	nameKeywordDelist = "退"
 )
 
-// 证券代码前缀：上海主板 6/5、深圳主板 00
-const (
-	codePrefixShanghai = '6'
-	codePrefixShanghaiB = '5'
-	codePrefixShenzhen = '0'
-	codeSecondShenzhenMain = '0'
-)
-
 // Criterion 单条条件：入参为合并后的 Stock，返回是否通过。
 type Criterion func(*model.Stock) bool
 
@@ -58,7 +51,21 @@ func Or(cs ...Criterion) Criterion {
 	}
 }
 
-// 默认策略阈值（成交额/量比/换手/涨幅/资金）
+// Not 取反：nil 的 Stock 返回 false（与 And/Or 一致）；c 为 nil 表示“无条件”（And/Or 中同样跳过），
+// 返回对非 nil Stock 恒真的条件。可组合出“非金叉但红柱增长”：And(Not(MacdGoldenCross), MacdHistogramGrow)。
+func Not(c Criterion) Criterion {
+	return func(s *model.Stock) bool {
+		if s == nil {
+			return false
+		}
+		if c == nil {
+			return true
+		}
+		return !c(s)
+	}
+}
+
+// 默认策略阈值（成交额/量比/换手/涨幅/资金），经 DefaultStrategyThresholds 供 DefaultStrategy 与展示共用
 const (
 	amountMin10Yi   = 1e9
 	volumeRatioMin  = 1.5
@@ -69,20 +76,32 @@ const (
 	netInflowMin1Yi = 1e8
 )
 
-// MainBoard 仅主板：上海 6/5 开头，深圳 00 开头。
+// MainBoard 仅主板：上海 60/5 开头，深圳 00 开头（不含科创板 688/689）。
 func MainBoard(s *model.Stock) bool {
-	code := strings.TrimSpace(s.Code)
-	if len(code) < 2 {
-		return false
-	}
-	switch code[0] {
-	case codePrefixShanghai, codePrefixShanghaiB:
+	return IsMainBoardCode(s.Code)
+}
+
+// IsMainBoardCode 按代码判断是否主板，供初选（StockQuote）与策略（Stock）共用。
+func IsMainBoardCode(code string) bool {
+	return model.BoardOf(code) == model.BoardMain
+}
+
+// InBoards 代码属于任一给定板块；boards 为空时不限板块。
+func InBoards(boards ...model.Board) Criterion {
+	return func(s *model.Stock) bool { return inBoards(s.Code, boards) }
+}
+
+func inBoards(code string, boards []model.Board) bool {
+	if len(boards) == 0 {
 		return true
-	case codePrefixShenzhen:
-		return len(code) >= 2 && code[1] == codeSecondShenzhenMain
-	default:
-		return false
 	}
+	b := model.BoardOf(code)
+	for _, want := range boards {
+		if b == want {
+			return true
+		}
+	}
+	return false
 }
 
 func AmountMin(min float64) Criterion {
@@ -93,6 +112,11 @@ func VolumeRatioMin(min float64) Criterion {
 	return func(s *model.Stock) bool { return s.VolumeRatio >= min }
 }
 
+// VolumeSurge 放量：当日成交量超过此前 5 日均量的 ratio 倍（均为手），均量缺失时不通过。
+func VolumeSurge(ratio float64) Criterion {
+	return func(s *model.Stock) bool { return s.VolMA5 > 0 && float64(s.Volume) > ratio*s.VolMA5 }
+}
+
 func TurnoverRateRange(min, max float64) Criterion {
 	return func(s *model.Stock) bool { return s.TurnoverRate >= min && s.TurnoverRate <= max }
 }
@@ -101,9 +125,74 @@ func ChangePctRange(min, max float64) Criterion {
 	return func(s *model.Stock) bool { return s.ChangePct >= min && s.ChangePct <= max }
 }
 
-func PriceAboveMA5(s *model.Stock) bool   { return s.Price > s.MA5 }
-func MA5AboveMA10(s *model.Stock) bool    { return s.MA5 > s.MA10 }
-func PriceAboveMA20(s *model.Stock) bool  { return s.Price > s.MA20 }
+// ChangePctMin 涨幅不低于 min。
+func ChangePctMin(min float64) Criterion {
+	return func(s *model.Stock) bool { return s.ChangePct >= min }
+}
+
+// ChangePctMax 涨幅不超过 max（用于排除已涨停、追不进的票）。
+func ChangePctMax(max float64) Criterion {
+	return func(s *model.Stock) bool { return s.ChangePct <= max }
+}
+
+// 均线比较类条件：样本不足（MAxValid 为 false，均线值为 0）时不通过，避免 0 被当作有效均线
+func PriceAboveMA5(s *model.Stock) bool  { return s.MA5Valid && s.Price > s.MA5 }
+func MA5AboveMA10(s *model.Stock) bool   { return s.MA5Valid && s.MA10Valid && s.MA5 > s.MA10 }
+func PriceAboveMA20(s *model.Stock) bool { return s.MA20Valid && s.Price > s.MA20 }
+
+// bullishMAPeriods 完整多头排列涉及的均线周期，即 Stock 上已计算的全部均线
+var bullishMAPeriods = []int{5, 10, 20, 60}
+
+// maOf 取 Stock 上周期为 period 的均线；不支持的周期或样本不足时 ok=false。
+func maOf(s *model.Stock, period int) (ma float64, ok bool) {
+	switch period {
+	case 5:
+		return s.MA5, s.MA5Valid
+	case 10:
+		return s.MA10, s.MA10Valid
+	case 20:
+		return s.MA20, s.MA20Valid
+	case 60:
+		return s.MA60, s.MA60Valid
+	}
+	return 0, false
+}
+
+// SupportedMAPeriod period 是否为 MAAlignment 可用的均线周期（5/10/20/60）。
+func SupportedMAPeriod(period int) bool {
+	for _, p := range bullishMAPeriods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// MAAlignment 均线多头排列：现价 > 最短周期均线 > … > 最长周期均线，periods 按从短到长比较（传入顺序不限）。
+// 任一均线样本不足或周期不受支持（见 SupportedMAPeriod）时不通过；periods 为空时只要求现价有效。
+func MAAlignment(periods ...int) Criterion {
+	ps := append([]int(nil), periods...)
+	sort.Ints(ps)
+	return func(s *model.Stock) bool {
+		prev := s.Price
+		if prev <= 0 {
+			return false
+		}
+		for _, p := range ps {
+			ma, ok := maOf(s, p)
+			if !ok || prev <= ma {
+				return false
+			}
+			prev = ma
+		}
+		return true
+	}
+}
+
+// BullishMAAlignment 完整均线多头排列：现价 > MA5 > MA10 > MA20 > MA60。
+func BullishMAAlignment(s *model.Stock) bool { return bullishMAAlignment(s) }
+
+var bullishMAAlignment = MAAlignment(bullishMAPeriods...)
 
 func ExcludeST(s *model.Stock) bool {
 	return !strings.Contains(strings.ToUpper(s.Name), nameKeywordST)
@@ -118,6 +207,22 @@ func NetInflowMin(min float64) Criterion {
 	}
 }
 
+// ContinuousNetInflow 近 days 日主力持续净流入（连续净流入天数 >= days）；
+// 资金流数据不足 days 天（未拉取或接口缺数据）时放行。
+func ContinuousNetInflow(days int) Criterion {
+	return func(s *model.Stock) bool {
+		if s.FundFlowDays < days {
+			return true
+		}
+		return s.MainNetInflowStreak >= days
+	}
+}
+
+// MoneyFlowAvailable 主力资金数据可用（见 Stock.MoneyFlowMissing），与资金条件组合可排除“无数据放行”的票。
+func MoneyFlowAvailable(s *model.Stock) bool {
+	return !s.MoneyFlowMissing
+}
+
 func MainForceInflowAboveOutflow(s *model.Stock) bool {
 	if s.MainForceInflow == 0 && s.MainForceOutflow == 0 {
 		return true
@@ -135,9 +240,21 @@ const (
 	volumeRatioMin1_2   = 1.2
 )
 
+// PreFilterOptions 初选的可选条件，零值表示不启用。
+type PreFilterOptions struct {
+	MarketCapMax float64       // 总市值上限(元)，>0 时启用，用于排除弹性小的超大盘股
+	Boards       []model.Board // 仅保留这些板块的代码，空为不限；全市场扫描时用它代替 fs 限定板块
+	Thresholds   *Thresholds   // 市值/PE/换手/量比阈值，应与策略一致以免初选误杀；nil 为 DefaultThresholds
+}
+
 // QuotePreFilter 仅用列表接口数据做初选：剔除 ST/退市、市值>50亿、PE 0-60、换手 3%-10%、量比>1.2。
 // 通过后再请求 K 线做技术面过滤，避免对全量股票请求 K 线，大幅缩短耗时。
 func QuotePreFilter(q *model.StockQuote) bool {
+	return QuotePreFilterWithOptions(q, PreFilterOptions{})
+}
+
+// QuotePreFilterWithOptions 在 QuotePreFilter 基础上叠加 opts 中启用的可选条件。
+func QuotePreFilterWithOptions(q *model.StockQuote, opts PreFilterOptions) bool {
 	if q == nil {
 		return false
 	}
@@ -147,16 +264,32 @@ func QuotePreFilter(q *model.StockQuote) bool {
 	if strings.Contains(q.Name, nameKeywordDelist) {
 		return false
 	}
-	if q.MarketCap < marketCapMin50Yi {
+	if !inBoards(q.Code, opts.Boards) {
+		return false
+	}
+	t := DefaultThresholds()
+	if opts.Thresholds != nil {
+		t = *opts.Thresholds
+	}
+	if opts.MarketCapMax > 0 {
+		t.MarketCapMax = opts.MarketCapMax
+	}
+	if q.MarketCap < t.MarketCapMin {
 		return false
 	}
-	if q.PE <= 0 || q.PE < peMin || q.PE > peMax {
+	if t.MarketCapMax > 0 && q.MarketCap > t.MarketCapMax {
 		return false
 	}
-	if q.TurnoverRate < turnoverRateMin3_10 || q.TurnoverRate > turnoverRateMax3_10 {
+	if q.PE <= 0 || q.PE < t.PEMin || q.PE > t.PEMax {
 		return false
 	}
-	if q.VolumeRatio < volumeRatioMin1_2 {
+	if q.TurnoverRate < t.TurnoverMin || q.TurnoverRate > t.TurnoverMax {
+		return false
+	}
+	if q.VolumeRatio < t.VolumeRatioMin {
+		return false
+	}
+	if t.AmountMin > 0 && q.Amount < t.AmountMin {
 		return false
 	}
 	return true
@@ -170,6 +303,16 @@ func MarketCapMin(min float64) Criterion {
 	return func(s *model.Stock) bool { return s.MarketCap >= min }
 }
 
+// MarketCapRange 总市值(元)在 [min, max]；max<=0 表示不设上限。
+func MarketCapRange(min, max float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.MarketCap < min {
+			return false
+		}
+		return max <= 0 || s.MarketCap <= max
+	}
+}
+
 func PERange(min, max float64) Criterion {
 	return func(s *model.Stock) bool {
 		if s.PE <= 0 {
@@ -179,10 +322,95 @@ func PERange(min, max float64) Criterion {
 	}
 }
 
+// PBRange 市净率在 [min, max]；PB 缺失（0）时放行。
+func PBRange(min, max float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.PB == 0 {
+			return true
+		}
+		return s.PB >= min && s.PB <= max
+	}
+}
+
+// ROEMin 净资产收益率(%) 不低于 min；ROE 缺失（0）时放行。
+func ROEMin(min float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.ROE == 0 {
+			return true
+		}
+		return s.ROE >= min
+	}
+}
+
+// RevenueGrowthMin 营收同比增速(%) 不低于 min；数据缺失（0）时放行。
+func RevenueGrowthMin(min float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.RevenueGrowth == 0 {
+			return true
+		}
+		return s.RevenueGrowth >= min
+	}
+}
+
+// IndustryRankTop 所属行业当日涨幅排名前 n（强势板块里的强势股）；行业数据缺失时放行。
+func IndustryRankTop(n int) Criterion {
+	return func(s *model.Stock) bool {
+		if s.IndustryRank == 0 {
+			return true
+		}
+		return s.IndustryRank <= n
+	}
+}
+
+// DrawdownRange 距近期高点回调幅度(%) 在 [min, max]；数据不足（HighN 为 0）时不通过。
+func DrawdownRange(min, max float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.HighN <= 0 {
+			return false
+		}
+		return s.DrawdownFromHigh >= min && s.DrawdownFromHigh <= max
+	}
+}
+
+// RSIRange 14 日 RSI 在 [min, max]，如 50~70 为强势但未超买；K 线不足（RSI14 为 0）时不通过。
+func RSIRange(min, max float64) Criterion {
+	return func(s *model.Stock) bool {
+		if s.RSI14 <= 0 {
+			return false
+		}
+		return s.RSI14 >= min && s.RSI14 <= max
+	}
+}
+
+// PriceBreakBollUpper 收盘（现价）突破 20 日布林带上轨，抓强势突破；K 线不足（BollUpper 为 0）时不通过。
+func PriceBreakBollUpper(s *model.Stock) bool {
+	return s.BollUpper > 0 && s.Price > s.BollUpper
+}
+
+// ATRPctMax 日均波动（ATR14/现价）不超过 max(%)，排除波动过大的票；K 线不足（ATRPct 为 0）时不通过。
+func ATRPctMax(max float64) Criterion {
+	return func(s *model.Stock) bool { return s.ATRPct > 0 && s.ATRPct <= max }
+}
+
+// UpStreakMin 连续上涨（收盘价逐日走高）至少 n 日。
+func UpStreakMin(n int) Criterion {
+	return func(s *model.Stock) bool { return n > 0 && s.UpStreak >= n }
+}
+
+// NewHighWithin 最新收盘价为近 days 日（含当日）收盘新高；K 线不足 days 根时不通过。
+func NewHighWithin(days int) Criterion {
+	return func(s *model.Stock) bool { return days > 0 && s.NewHighDays >= days }
+}
+
 func MA60Up(s *model.Stock) bool {
 	return s.MA60Up
 }
 
+// MA20CrossUpMA60 MA20 当日刚上穿 MA60（均线金叉，中期买点）
+func MA20CrossUpMA60(s *model.Stock) bool {
+	return s.MA20CrossUpMA60
+}
+
 // MacdHistogramGrow 红柱较昨日增长且今日为红柱
 func MacdHistogramGrow(s *model.Stock) bool {
 	return s.MacdHistogram > 0 && s.MacdHistogram > s.MacdHistogramPrev
@@ -197,34 +425,59 @@ func MacdMomentum(s *model.Stock) bool {
 	return MacdHistogramGrow(s) || MacdGoldenCross(s)
 }
 
+// TrendMomentumOptions 趋势动能策略的可选条件，零值表示不启用。
+type TrendMomentumOptions struct {
+	ChangePctMax float64 // 涨幅上限(%)，>0 时启用，如 9.5 可避开已涨停的票
+	MarketCapMax float64 // 总市值上限(元)，>0 时启用
+}
+
 // TrendMomentumStrategy 复合策略：基础过滤 + 趋势 + 动能 + 成交量；结果由调用方按涨幅排序取前 N。
 func TrendMomentumStrategy() Criterion {
+	return TrendMomentumStrategyWithOptions(TrendMomentumOptions{})
+}
+
+// TrendMomentumStrategyWithOptions 在 TrendMomentumStrategy 基础上叠加 opts 中启用的可选条件。
+func TrendMomentumStrategyWithOptions(opts TrendMomentumOptions) Criterion {
+	t := DefaultThresholds()
+	t.ChangePctMax = opts.ChangePctMax
+	t.MarketCapMax = opts.MarketCapMax
+	return TrendMomentumStrategyFrom(t)
+}
+
+// 超跌反弹阈值：距 60 日高点回调 15%~30%
+const (
+	drawdownMin = 15
+	drawdownMax = 30
+)
+
+// BottomFishingStrategy 抄底策略：剔除 ST/退市、从近 60 日高点回调 15%~30%，并以 MACD 低位金叉确认止跌。
+func BottomFishingStrategy() Criterion {
 	return And(
 		ExcludeST,
 		ExcludeDelisted,
-		MarketCapMin(marketCapMin50Yi),
-		PERange(peMin, peMax),
-		PriceAboveMA20,
-		MA60Up,
-		MacdMomentum,
-		TurnoverRateRange(turnoverRateMin3_10, turnoverRateMax3_10),
-		VolumeRatioMin(volumeRatioMin1_2),
+		DrawdownRange(drawdownMin, drawdownMax),
+		MacdGoldenCross,
 	)
 }
 
 // DefaultStrategy 当前选股策略：主板、成交额≥10亿、量比≥1.5、换手 3%~12%、涨幅 3.5%~7%、均线多头、剔除 ST、资金条件。
 func DefaultStrategy() Criterion {
+	return DefaultStrategyFrom(DefaultStrategyThresholds())
+}
+
+// DefaultStrategyFrom 按阈值构建 DefaultStrategy，只使用成交额、量比、换手、涨幅与主力净流入几项。
+func DefaultStrategyFrom(t Thresholds) Criterion {
 	return And(
 		MainBoard,
-		AmountMin(amountMin10Yi),
-		VolumeRatioMin(volumeRatioMin),
-		TurnoverRateRange(turnoverRateMin, turnoverRateMax),
-		ChangePctRange(changePctMin, changePctMax),
+		AmountMin(t.AmountMin),
+		VolumeRatioMin(t.VolumeRatioMin),
+		TurnoverRateRange(t.TurnoverMin, t.TurnoverMax),
+		ChangePctRange(t.ChangePctMin, t.ChangePctMax),
 		PriceAboveMA5,
 		MA5AboveMA10,
 		PriceAboveMA20,
 		ExcludeST,
-		NetInflowMin(netInflowMin1Yi),
+		NetInflowMin(t.NetInflowMin),
 		MainForceInflowAboveOutflow,
 	)
 }
@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"testing"
+
+	"stockMaxWin/internal/model"
+)
+
+func constCriterion(v bool) Criterion {
+	return func(*model.Stock) bool { return v }
+}
+
+// 德摩根律：Not(And(a,b)) 与 Or(Not(a),Not(b)) 对所有真值组合结果相同，Not(Or(a,b)) 与 And(Not(a),Not(b)) 同理。
+func TestNotDeMorgan(t *testing.T) {
+	s := &model.Stock{}
+	for _, av := range []bool{false, true} {
+		for _, bv := range []bool{false, true} {
+			a, b := constCriterion(av), constCriterion(bv)
+			if got, want := Not(And(a, b))(s), Or(Not(a), Not(b))(s); got != want {
+				t.Errorf("a=%v b=%v: Not(And) = %v, Or(Not,Not) = %v", av, bv, got, want)
+			}
+			if got, want := Not(Or(a, b))(s), And(Not(a), Not(b))(s); got != want {
+				t.Errorf("a=%v b=%v: Not(Or) = %v, And(Not,Not) = %v", av, bv, got, want)
+			}
+		}
+	}
+}
+
+func TestCombinatorsEmptyAndNil(t *testing.T) {
+	s := &model.Stock{}
+	tests := []struct {
+		name  string
+		c     Criterion
+		stock *model.Stock
+		want  bool
+	}{
+		{"And 无参数恒真", And(), s, true},
+		{"Or 无参数恒假", Or(), s, false},
+		{"And 跳过 nil 条件", And(nil, constCriterion(true)), s, true},
+		{"Or 跳过 nil 条件", Or(nil, constCriterion(false)), s, false},
+		{"Not(nil) 恒真", Not(nil), s, true},
+		{"Not 取反", Not(constCriterion(true)), s, false},
+		{"And nil Stock", And(), nil, false},
+		{"Or nil Stock", Or(constCriterion(true)), nil, false},
+		{"Not nil Stock", Not(constCriterion(false)), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c(tt.stock); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// DefaultStrategy 与 DescribeDefaultStrategy 同出自 DefaultStrategyThresholds：恰在展示的阈值上通过，低于任一下限即不通过。
+func TestDefaultStrategyMatchesDescribe(t *testing.T) {
+	want := map[string]float64{
+		"turnover_min":     3,
+		"turnover_max":     12,
+		"volume_ratio_min": 1.5,
+		"change_pct_min":   3.5,
+		"change_pct_max":   7,
+		"amount_min":       10,
+		"net_inflow_min":   1,
+	}
+	infos := DescribeDefaultStrategy()
+	if len(infos) != len(want) {
+		t.Fatalf("DescribeDefaultStrategy 共 %d 项，want %d", len(infos), len(want))
+	}
+	for _, info := range infos {
+		if v, ok := want[info.Key]; !ok || v != info.Value {
+			t.Errorf("%s = %v, want %v (listed=%v)", info.Key, info.Value, v, ok)
+		}
+	}
+
+	th := DefaultStrategyThresholds()
+	base := func() *model.Stock {
+		return &model.Stock{
+			Code: "600000", Name: "浦发银行", Price: 11,
+			MA5: 10.5, MA5Valid: true, MA10: 10, MA10Valid: true, MA20: 9.5, MA20Valid: true,
+			Amount: th.AmountMin, VolumeRatio: th.VolumeRatioMin,
+			TurnoverRate: th.TurnoverMin, ChangePct: th.ChangePctMin,
+			NetInflow: th.NetInflowMin, MainForceInflow: 2e8, MainForceOutflow: 1e8,
+		}
+	}
+	if !DefaultStrategy()(base()) {
+		t.Fatal("阈值边界上的股票应通过 DefaultStrategy")
+	}
+	tests := []struct {
+		name   string
+		modify func(s *model.Stock)
+	}{
+		{"成交额不足", func(s *model.Stock) { s.Amount = th.AmountMin - 1 }},
+		{"量比不足", func(s *model.Stock) { s.VolumeRatio = th.VolumeRatioMin - 0.01 }},
+		{"换手过高", func(s *model.Stock) { s.TurnoverRate = th.TurnoverMax + 0.01 }},
+		{"涨幅过高", func(s *model.Stock) { s.ChangePct = th.ChangePctMax + 0.01 }},
+		{"净流入不足", func(s *model.Stock) { s.NetInflow = th.NetInflowMin - 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := base()
+			tt.modify(s)
+			if DefaultStrategy()(s) {
+				t.Error("DefaultStrategy 应不通过")
+			}
+		})
+	}
+}
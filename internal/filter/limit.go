@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"strings"
+
+	"stockMaxWin/internal/model"
+)
+
+// 各板块涨停幅度(%)：主板 10%，创业板/科创板 20%，北交所 30%，ST 5%
+const (
+	limitUpMainBoard = 10
+	limitUpGrowth    = 20
+	limitUpBSE       = 30
+	limitUpST        = 5
+)
+
+// LimitUpPct 按代码所属板块（及是否 ST）返回涨停幅度(%)。
+// 创业板、科创板 20%，北交所 30%，其余按主板。
+func LimitUpPct(code, name string) float64 {
+	switch model.BoardOf(code) {
+	case model.BoardChiNext, model.BoardSTAR:
+		return limitUpGrowth
+	case model.BoardBSE:
+		return limitUpBSE
+	}
+	if strings.Contains(strings.ToUpper(name), nameKeywordST) {
+		return limitUpST
+	}
+	return limitUpMainBoard
+}
+
+// RoomToLimitUp 距涨停的剩余空间（涨停幅度 - 当日涨幅，单位 %）在 [min, max]，
+// 如 RoomToLimitUp(3, 6) 选“有上攻空间又没封死”的票。
+func RoomToLimitUp(min, max float64) Criterion {
+	return func(s *model.Stock) bool {
+		room := LimitUpPct(s.Code, s.Name) - s.ChangePct
+		return room >= min && room <= max
+	}
+}
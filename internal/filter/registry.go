@@ -0,0 +1,153 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Params 条件参数，对应配置里的数组，如 "turnover_range": [3, 10]。
+type Params []float64
+
+// Factory 按参数构造条件，参数个数不对时返回错误。
+type Factory func(p Params) (Criterion, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register 注册条件名，重复注册覆盖旧值。新增条件只需在此注册一次，配置即可按名引用。
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = f
+}
+
+// Registered 返回已注册的条件名（排序后），便于文档与校验。
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build 按名查表构造单个条件。
+func Build(name string, p Params) (Criterion, error) {
+	registryMu.RLock()
+	f, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("filter: unknown criterion %q", name)
+	}
+	c, err := f(p)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %s: %w", name, err)
+	}
+	return c, nil
+}
+
+// BuildAll 按名构造全部条件并用 And 组合；按名称排序构造，保证结果确定。
+func BuildAll(specs map[string]Params) (Criterion, error) {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	cs := make([]Criterion, 0, len(names))
+	for _, name := range names {
+		c, err := Build(name, specs[name])
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return And(cs...), nil
+}
+
+// noParam 包装无参数条件。
+func noParam(c Criterion) Factory {
+	return func(p Params) (Criterion, error) {
+		if len(p) != 0 {
+			return nil, fmt.Errorf("expects no params, got %d", len(p))
+		}
+		return c, nil
+	}
+}
+
+func oneParam(build func(float64) Criterion) Factory {
+	return func(p Params) (Criterion, error) {
+		if len(p) != 1 {
+			return nil, fmt.Errorf("expects 1 param, got %d", len(p))
+		}
+		return build(p[0]), nil
+	}
+}
+
+func twoParams(build func(float64, float64) Criterion) Factory {
+	return func(p Params) (Criterion, error) {
+		if len(p) != 2 {
+			return nil, fmt.Errorf("expects 2 params, got %d", len(p))
+		}
+		return build(p[0], p[1]), nil
+	}
+}
+
+// maPeriodsParams ma_alignment 的参数为若干均线周期，如 [5, 20, 60]；不支持的周期在构造时报错。
+func maPeriodsParams(p Params) (Criterion, error) {
+	if len(p) == 0 {
+		return nil, fmt.Errorf("expects at least 1 param, got 0")
+	}
+	periods := make([]int, len(p))
+	for i, v := range p {
+		periods[i] = int(v)
+		if float64(periods[i]) != v || !SupportedMAPeriod(periods[i]) {
+			return nil, fmt.Errorf("unsupported MA period %v (want 5/10/20/60)", v)
+		}
+	}
+	return MAAlignment(periods...), nil
+}
+
+func init() {
+	Register("main_board", noParam(MainBoard))
+	Register("exclude_st", noParam(ExcludeST))
+	Register("exclude_delisted", noParam(ExcludeDelisted))
+	Register("amount_min", oneParam(AmountMin))
+	Register("volume_ratio_min", oneParam(VolumeRatioMin))
+	Register("volume_surge", oneParam(VolumeSurge))
+	Register("turnover_range", twoParams(TurnoverRateRange))
+	Register("change_pct_range", twoParams(ChangePctRange))
+	Register("change_pct_max", oneParam(ChangePctMax))
+	Register("price_above_ma5", noParam(PriceAboveMA5))
+	Register("ma5_above_ma10", noParam(MA5AboveMA10))
+	Register("price_above_ma20", noParam(PriceAboveMA20))
+	Register("bullish_ma_alignment", noParam(BullishMAAlignment))
+	Register("ma_alignment", maPeriodsParams)
+	Register("net_inflow_min", oneParam(NetInflowMin))
+	Register("main_force_in_above_out", noParam(MainForceInflowAboveOutflow))
+	Register("money_flow_available", noParam(MoneyFlowAvailable))
+	Register("continuous_net_inflow", oneParam(func(n float64) Criterion { return ContinuousNetInflow(int(n)) }))
+	Register("market_cap_min", oneParam(MarketCapMin))
+	Register("market_cap_range", twoParams(MarketCapRange))
+	Register("pe_range", twoParams(PERange))
+	Register("pb_range", twoParams(PBRange))
+	Register("roe_min", oneParam(ROEMin))
+	Register("revenue_growth_min", oneParam(RevenueGrowthMin))
+	Register("industry_rank_top", oneParam(func(n float64) Criterion { return IndustryRankTop(int(n)) }))
+	Register("drawdown_range", twoParams(DrawdownRange))
+	Register("room_to_limit_up", twoParams(RoomToLimitUp))
+	Register("rsi_range", twoParams(RSIRange))
+	Register("price_break_boll_upper", noParam(PriceBreakBollUpper))
+	Register("atr_pct_max", oneParam(ATRPctMax))
+	Register("up_streak_min", oneParam(func(n float64) Criterion { return UpStreakMin(int(n)) }))
+	Register("new_high_within", oneParam(func(n float64) Criterion { return NewHighWithin(int(n)) }))
+	Register("ma60_up", noParam(MA60Up))
+	Register("ma20_cross_up_ma60", noParam(MA20CrossUpMA60))
+	Register("macd_histogram_grow", noParam(MacdHistogramGrow))
+	Register("macd_golden_cross", noParam(MacdGoldenCross))
+	Register("macd_momentum", noParam(MacdMomentum))
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"math"
+
+	"stockMaxWin/internal/model"
+)
+
+// maxRoundDigits 比较前舍入的最大小数位数，超过按此值处理
+const maxRoundDigits = 8
+
+// Rounded 包装条件：判断前把价格、均线、涨幅、换手、量比、估值等展示类字段舍入到 digits 位小数，
+// 使过滤结果与邮件 %.2f 展示一致（避免“展示 3.50 却因 3.4999 被判不通过”）。
+// 只作用于判断用的副本，不修改原 Stock；digits < 0 时原样返回（全精度）。
+func Rounded(c Criterion, digits int) Criterion {
+	if digits < 0 {
+		return c
+	}
+	if digits > maxRoundDigits {
+		digits = maxRoundDigits
+	}
+	return func(s *model.Stock) bool {
+		if s == nil {
+			return c(s)
+		}
+		r := *s
+		roundStock(&r, digits)
+		return c(&r)
+	}
+}
+
+// roundStock 舍入展示精度的字段；MACD 柱、成交额、市值、资金流等量级或展示精度不同，保持原值。
+func roundStock(s *model.Stock, digits int) {
+	for _, f := range []*float64{
+		&s.Price, &s.MA5, &s.MA10, &s.MA20, &s.MA60,
+		&s.ChangePct, &s.VolumeRatio, &s.TurnoverRate,
+		&s.PE, &s.PB, &s.ROE, &s.RevenueGrowth, &s.ProfitGrowth,
+		&s.HighN, &s.DrawdownFromHigh, &s.RSI14,
+		&s.BollUpper, &s.BollMid, &s.BollLower, &s.ATR14, &s.ATRPct,
+	} {
+		*f = roundTo(*f, digits)
+	}
+}
+
+func roundTo(v float64, digits int) float64 {
+	p := math.Pow10(digits)
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"sort"
+
+	"stockMaxWin/internal/model"
+)
+
+// 初选强度分默认权重：量比、换手、涨幅
+const (
+	defaultStrengthWeightVolumeRatio  = 0.4
+	defaultStrengthWeightTurnoverRate = 0.3
+	defaultStrengthWeightChangePct    = 0.3
+)
+
+// StrengthWeights 初选强度分各项权重；各项先在本批候选内按 min-max 归一化到 0~1 再加权。
+type StrengthWeights struct {
+	VolumeRatio  float64
+	TurnoverRate float64
+	ChangePct    float64
+}
+
+func DefaultStrengthWeights() StrengthWeights {
+	return StrengthWeights{
+		VolumeRatio:  defaultStrengthWeightVolumeRatio,
+		TurnoverRate: defaultStrengthWeightTurnoverRate,
+		ChangePct:    defaultStrengthWeightChangePct,
+	}
+}
+
+// StrengthScores 计算每只候选的强度分（与 quotes 下标一一对应）。
+func StrengthScores(quotes []model.StockQuote, w StrengthWeights) []float64 {
+	vr := normalize(quotes, func(q *model.StockQuote) float64 { return q.VolumeRatio })
+	tr := normalize(quotes, func(q *model.StockQuote) float64 { return q.TurnoverRate })
+	cp := normalize(quotes, func(q *model.StockQuote) float64 { return q.ChangePct })
+	scores := make([]float64, len(quotes))
+	for i := range quotes {
+		scores[i] = w.VolumeRatio*vr[i] + w.TurnoverRate*tr[i] + w.ChangePct*cp[i]
+	}
+	return scores
+}
+
+// SortByStrength 按强度分降序排列候选（原地，同分保持原顺序）。
+func SortByStrength(quotes []model.StockQuote, w StrengthWeights) {
+	scores := StrengthScores(quotes, w)
+	idx := make([]int, len(quotes))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+	sorted := make([]model.StockQuote, len(quotes))
+	for i, j := range idx {
+		sorted[i] = quotes[j]
+	}
+	copy(quotes, sorted)
+}
+
+// normalize 把 v 在本批内线性映射到 0~1；全部相同时均为 0。
+func normalize(quotes []model.StockQuote, v func(*model.StockQuote) float64) []float64 {
+	out := make([]float64, len(quotes))
+	if len(quotes) == 0 {
+		return out
+	}
+	min, max := v(&quotes[0]), v(&quotes[0])
+	for i := range quotes {
+		x := v(&quotes[i])
+		if x < min {
+			min = x
+		}
+		if x > max {
+			max = x
+		}
+	}
+	if max == min {
+		return out
+	}
+	for i := range quotes {
+		out[i] = (v(&quotes[i]) - min) / (max - min)
+	}
+	return out
+}
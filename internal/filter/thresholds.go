@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// changePctNoMin 涨跌幅下限的“不限”取值（A 股跌幅不会超过 100%）
+const changePctNoMin = -100
+
+// yi 亿元，市值阈值展示单位
+const yi = 1e8
+
+// Thresholds 策略与初选共用的数值阈值；市值、成交额、净流入单位为元，涨幅/换手单位为 %。
+// MarketCapMax、ChangePctMax <= 0 表示不设上限，AmountMin、NetInflowMin <= 0 表示不设下限。
+type Thresholds struct {
+	MarketCapMin   float64
+	MarketCapMax   float64
+	PEMin          float64
+	PEMax          float64
+	TurnoverMin    float64
+	TurnoverMax    float64
+	VolumeRatioMin float64
+	ChangePctMin   float64
+	ChangePctMax   float64
+	AmountMin      float64
+	NetInflowMin   float64
+}
+
+// DefaultThresholds 内置阈值：市值>50亿、PE 0-60、换手 3%-10%、量比>1.2，市值与涨幅不设上限。
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MarketCapMin:   marketCapMin50Yi,
+		PEMin:          peMin,
+		PEMax:          peMax,
+		TurnoverMin:    turnoverRateMin3_10,
+		TurnoverMax:    turnoverRateMax3_10,
+		VolumeRatioMin: volumeRatioMin1_2,
+		ChangePctMin:   changePctNoMin,
+	}
+}
+
+// DefaultStrategyThresholds DefaultStrategy 的阈值：成交额≥10亿、量比≥1.5、换手 3%~12%、涨幅 3.5%~7%、主力净流入≥1亿。
+// DefaultStrategy 不看市值与 PE，这几项保持零值，展示时由 DescribeDefaultStrategy 略去。
+func DefaultStrategyThresholds() Thresholds {
+	return Thresholds{
+		TurnoverMin:    turnoverRateMin,
+		TurnoverMax:    turnoverRateMax,
+		VolumeRatioMin: volumeRatioMin,
+		ChangePctMin:   changePctMin,
+		ChangePctMax:   changePctMax,
+		AmountMin:      amountMin10Yi,
+		NetInflowMin:   netInflowMin1Yi,
+	}
+}
+
+// TrendMomentumStrategyFrom 按阈值构建趋势动能策略：基础过滤 + 趋势（站上 MA20、MA60 向上）+ MACD 动能 + 成交量。
+func TrendMomentumStrategyFrom(t Thresholds) Criterion {
+	cs := []Criterion{
+		ExcludeST,
+		ExcludeDelisted,
+		MarketCapRange(t.MarketCapMin, t.MarketCapMax),
+		PERange(t.PEMin, t.PEMax),
+		PriceAboveMA20,
+		MA60Up,
+		MacdMomentum,
+		TurnoverRateRange(t.TurnoverMin, t.TurnoverMax),
+		VolumeRatioMin(t.VolumeRatioMin),
+	}
+	if t.ChangePctMin > changePctNoMin {
+		cs = append(cs, ChangePctMin(t.ChangePctMin))
+	}
+	if t.ChangePctMax > 0 {
+		cs = append(cs, ChangePctMax(t.ChangePctMax))
+	}
+	if t.AmountMin > 0 {
+		cs = append(cs, AmountMin(t.AmountMin))
+	}
+	if t.NetInflowMin > 0 {
+		cs = append(cs, NetInflowMin(t.NetInflowMin))
+	}
+	return And(cs...)
+}
+
+// ThresholdInfo 一项阈值的键名（同 strategy.json 字段）、含义、取值与单位，供 /status、邮件与 CLI 展示，避免各处重复硬编码。
+// 市值以亿元展示；上限类取 0、涨幅下限取 -100 表示不限。
+type ThresholdInfo struct {
+	Key         string  `json:"key"`
+	Description string  `json:"description"`
+	Value       float64 `json:"value"`
+	Unit        string  `json:"unit,omitempty"`
+}
+
+// Describe 按固定顺序列出全部阈值。
+func (t Thresholds) Describe() []ThresholdInfo {
+	return []ThresholdInfo{
+		{Key: "market_cap_min", Description: "总市值下限", Value: t.MarketCapMin / yi, Unit: "亿"},
+		{Key: "market_cap_max", Description: "总市值上限（0 为不限）", Value: t.MarketCapMax / yi, Unit: "亿"},
+		{Key: "pe_min", Description: "市盈率下限（亏损股 PE<=0 始终排除）", Value: t.PEMin},
+		{Key: "pe_max", Description: "市盈率上限", Value: t.PEMax},
+		{Key: "turnover_min", Description: "换手率下限", Value: t.TurnoverMin, Unit: "%"},
+		{Key: "turnover_max", Description: "换手率上限", Value: t.TurnoverMax, Unit: "%"},
+		{Key: "volume_ratio_min", Description: "量比下限", Value: t.VolumeRatioMin},
+		{Key: "change_pct_min", Description: "当日涨幅下限（-100 为不限）", Value: t.ChangePctMin, Unit: "%"},
+		{Key: "change_pct_max", Description: "当日涨幅上限（0 为不限）", Value: t.ChangePctMax, Unit: "%"},
+		{Key: "amount_min", Description: "当日成交额下限（0 为不限）", Value: t.AmountMin / yi, Unit: "亿"},
+		{Key: "net_inflow_min", Description: "主力净流入下限（0 为不限）", Value: t.NetInflowMin / yi, Unit: "亿"},
+	}
+}
+
+// defaultStrategyUnusedKeys DefaultStrategy 不使用的阈值键（市值与 PE）
+var defaultStrategyUnusedKeys = map[string]bool{
+	"market_cap_min": true,
+	"market_cap_max": true,
+	"pe_min":         true,
+	"pe_max":         true,
+}
+
+// DescribeDefaultStrategy 列出 DefaultStrategy 实际使用的阈值，与 DefaultStrategy 同出自 DefaultStrategyThresholds。
+func DescribeDefaultStrategy() []ThresholdInfo {
+	var out []ThresholdInfo
+	for _, info := range DefaultStrategyThresholds().Describe() {
+		if !defaultStrategyUnusedKeys[info.Key] {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// Summary 一行描述趋势动能策略及其阈值，如“剔除ST/退市·市值>50亿·PE 0-60·站上MA20·…”，供邮件说明使用。
+func (t Thresholds) Summary() string {
+	parts := []string{"剔除ST/退市", "市值>" + fmtNum(t.MarketCapMin/yi) + "亿"}
+	if t.MarketCapMax > 0 {
+		parts[1] = "市值" + fmtNum(t.MarketCapMin/yi) + "-" + fmtNum(t.MarketCapMax/yi) + "亿"
+	}
+	parts = append(parts,
+		"PE "+fmtNum(t.PEMin)+"-"+fmtNum(t.PEMax),
+		"站上MA20", "MA60向上", "MACD红柱增或金叉",
+		"换手"+fmtNum(t.TurnoverMin)+"%-"+fmtNum(t.TurnoverMax)+"%",
+		"量比>"+fmtNum(t.VolumeRatioMin),
+	)
+	if t.ChangePctMin > changePctNoMin {
+		parts = append(parts, fmt.Sprintf("涨幅>=%s%%", fmtNum(t.ChangePctMin)))
+	}
+	if t.ChangePctMax > 0 {
+		parts = append(parts, fmt.Sprintf("涨幅<=%s%%", fmtNum(t.ChangePctMax)))
+	}
+	if t.AmountMin > 0 {
+		parts = append(parts, "成交额>="+fmtNum(t.AmountMin/yi)+"亿")
+	}
+	if t.NetInflowMin > 0 {
+		parts = append(parts, "主力净流入>="+fmtNum(t.NetInflowMin/yi)+"亿")
+	}
+	return strings.Join(parts, "·")
+}
+
+func fmtNum(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,92 @@
+// Package history 以 JSONL 追加落盘每轮入选股票，供事后统计选股质量。
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"stockMaxWin/internal/model"
+)
+
+// 文件权限与单行上限
+const (
+	filePerm     = 0o644
+	dirPerm      = 0o755
+	maxLineBytes = 1 << 20
+	dateLayout   = "2006-01-02"
+)
+
+// Record 一次入选记录（每只股票一行）。
+type Record struct {
+	RunAt     time.Time `json:"run_at"`
+	TraceID   string    `json:"trace_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Price     float64   `json:"price"`
+	ChangePct float64   `json:"change_pct"`
+	Cooldown  bool      `json:"cooldown,omitempty"` // 推送时处于冷却期，不重新计算冷却
+	Pushed    bool      `json:"pushed,omitempty"`   // 本轮至少经一个渠道送达；未配置渠道或全部失败时为 false，不计入冷却
+}
+
+// Date 入选日期（本地时区 YYYY-MM-DD），与 K 线日期格式一致。
+func (r Record) Date() string {
+	return r.RunAt.Local().Format(dateLayout)
+}
+
+// Append 把本轮入选追加到 path（不存在则创建）。
+func Append(path string, runAt time.Time, traceID string, stocks []*model.Stock) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		rec := Record{RunAt: runAt, TraceID: traceID, Code: s.Code, Name: s.Name, Price: s.Price, ChangePct: s.ChangePct, Cooldown: s.Cooldown, Pushed: s.Delivered}
+		if err := enc.Encode(rec); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load 读取全部记录；文件不存在返回空，单行解析失败跳过。
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var out []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil || rec.Code == "" {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,123 @@
+// Package llm 可选的大模型点评：把本轮入选摘要发给本地 Ollama，返回一句自然语言点评插入邮件。
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/model"
+)
+
+// Ollama 默认地址、超时与提示词
+const (
+	DefaultOllamaEndpoint = "http://localhost:11434"
+	defaultTimeout        = 30 * time.Second
+	generatePath          = "/api/generate"
+	maxPromptStocks       = 30
+	promptHeader          = "你是 A 股盘面分析助手。以下是今日量化选股的入选股票（代码 名称 行业 最强概念 涨幅 换手 量比）。" +
+		"请用一到两句中文点评整体特征（如行业/概念集中度、量能），不要给出买卖建议，不要逐只复述。\n"
+)
+
+// Commenter 根据入选股票生成点评文本。
+type Commenter interface {
+	Comment(ctx context.Context, stocks []*model.Stock) (string, error)
+}
+
+// OllamaConfig Ollama 服务地址与模型名，Endpoint 为空时用本机默认端口。
+type OllamaConfig struct {
+	Endpoint string
+	Model    string
+	Timeout  time.Duration
+}
+
+// Ollama 通过 /api/generate（非流式）生成点评。
+type Ollama struct {
+	cfg    OllamaConfig
+	client *http.Client
+}
+
+func NewOllama(cfg OllamaConfig) *Ollama {
+	if strings.TrimSpace(cfg.Endpoint) == "" {
+		cfg.Endpoint = DefaultOllamaEndpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Ollama{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+type generateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type generateResponse struct {
+	Response string `json:"response"`
+	Error    string `json:"error"`
+}
+
+func (o *Ollama) Comment(ctx context.Context, stocks []*model.Stock) (string, error) {
+	if len(stocks) == 0 {
+		return "", nil
+	}
+	body, err := json.Marshal(generateRequest{Model: o.cfg.Model, Prompt: BuildPrompt(stocks)})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.cfg.Endpoint+generatePath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("ollama read body: %w", err)
+	}
+	var r generateResponse
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", fmt.Errorf("ollama decode (http %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || r.Error != "" {
+		return "", fmt.Errorf("ollama http %d: %s", resp.StatusCode, r.Error)
+	}
+	return strings.TrimSpace(r.Response), nil
+}
+
+// BuildPrompt 把入选股票压缩为每行一只的摘要，最多 maxPromptStocks 只。
+func BuildPrompt(stocks []*model.Stock) string {
+	var b strings.Builder
+	b.WriteString(promptHeader)
+	n := 0
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		if n >= maxPromptStocks {
+			break
+		}
+		n++
+		b.WriteString(fmt.Sprintf("%s %s %s %s %.2f%% %.2f%% %.2f\n",
+			s.Code, s.Name, orDash(s.Industry), orDash(s.TopConcept), s.ChangePct, s.TurnoverRate, s.VolumeRatio))
+	}
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 当日汇总邮件
+const (
+	subjectDailySummary = "今日关注池汇总"
+	titleDailySummary   = "今日关注池"
+	firstSeenTimeFormat = "15:04"
+)
+
+// DailyEntry 当日累计池中的一只票：最近一次入选时的数据、当天首次入选时间与入选轮数。
+type DailyEntry struct {
+	Stock     *model.Stock
+	FirstSeen time.Time
+	Hits      int
+}
+
+// SendDailySummary 收盘后发送当日累计池汇总（按首次入选时间排列），entries 为空时不发。
+func SendDailySummary(ctx context.Context, cfg *SMTPConfig, day string, entries []DailyEntry) error {
+	if cfg == nil || !cfg.Enabled() {
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	trace.Log(ctx, "mail: SendDailySummary day=%s count=%d", day, len(entries))
+	body := buildDailySummaryHTML(day, entries, cfg.theme())
+	return send(ctx, cfg, subjectDailySummary+" · "+day, body, parseRecipients(cfg.To))
+}
+
+func buildDailySummaryHTML(day string, entries []DailyEntry, t Theme) string {
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html><html><head><meta charset="` + htmlCharset + `"><title>` + titleDailySummary + `</title></head><body style="` + t.bodyStyle() + `">`)
+	b.WriteString(`<div style="` + t.cardStyle("960px") + `">`)
+	b.WriteString(fmt.Sprintf(`<h2 style="margin:0 0 8px;color:%s;">%s 今日关注池（共 %d 只）</h2>`, t.Primary, escapeHTML(day), len(entries)))
+	b.WriteString(`<p style="color:` + t.Muted + `;">盘中各轮入选去重合并，按当天首次入选时间排列；涨幅为最近一次入选时的数据。</p>`)
+	b.WriteString(`<table border="1" cellspacing="0" cellpadding="8" style="border-collapse:collapse;font-size:14px;border-color:` + t.Border + `;">`)
+	b.WriteString(`<thead><tr style="background:` + t.SurfaceAlt + `;">`)
+	for _, h := range []string{"代码", "名称", "首次入选", "入选轮数", "涨幅(%)", "主营"} {
+		b.WriteString(`<th style="color:` + t.Primary + `;">` + h + "</th>")
+	}
+	b.WriteString(`</tr></thead><tbody>`)
+	for _, e := range entries {
+		s := e.Stock
+		if s == nil {
+			continue
+		}
+		business := s.MainBusiness
+		if business == "" {
+			business = emptyCellValue
+		}
+		b.WriteString("<tr>")
+		b.WriteString("<td>" + escapeHTML(s.Code) + "</td>")
+		b.WriteString("<td>" + escapeHTML(s.Name) + "</td>")
+		b.WriteString("<td>" + e.FirstSeen.Format(firstSeenTimeFormat) + "</td>")
+		b.WriteString("<td>" + strconv.Itoa(e.Hits) + "</td>")
+		b.WriteString(`<td style="color:` + t.changeColor(s.ChangePct) + `;">` + strconv.FormatFloat(s.ChangePct, 'f', 2, 64) + "</td>")
+		b.WriteString("<td>" + escapeHTML(business) + "</td>")
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</tbody></table>")
+	b.WriteString(footerHTML(t))
+	b.WriteString("</div></body></html>")
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"stockMaxWin/internal/export"
+	"stockMaxWin/internal/model"
+)
+
+// base64 正文每行长度（RFC 2045 要求不超过 76）
+const mimeLineLen = 76
+
+// mailBody 一封邮件的两种正文：纯文本与 HTML。Plain 为空时由 HTML 粗略转换得到；Attachments 非空时整封改为 multipart/mixed。
+type mailBody struct {
+	Plain       string
+	HTML        string
+	Attachments []attachment
+}
+
+// attachment 邮件附件：Name 为文件名（可含中文），ContentType 如 "text/csv; charset=UTF-8"。
+type attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// buildMessage 构造 multipart/alternative 邮件（头 + 正文）：text/plain 在前、text/html 在后，
+// 客户端按能力选择最后一个可显示的部分。boundary 由 multipart.Writer 随机生成，两部分均 base64 编码，
+// 中文主题按 RFC 2047 编码。带附件时外层为 multipart/mixed：第一部分是上述 alternative 正文，其后每个附件一部分。
+func buildMessage(from string, to []string, subject string, body mailBody) ([]byte, error) {
+	alt, altBoundary, err := buildAlternative(body)
+	if err != nil {
+		return nil, err
+	}
+	contentType := "multipart/alternative; boundary=" + strconv.Quote(altBoundary)
+	parts := alt
+	if len(body.Attachments) > 0 {
+		var mixed bytes.Buffer
+		mw := multipart.NewWriter(&mixed)
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(alt); err != nil {
+			return nil, err
+		}
+		for _, a := range body.Attachments {
+			if err := writeAttachment(mw, a); err != nil {
+				return nil, err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		contentType = "multipart/mixed; boundary=" + strconv.Quote(mw.Boundary())
+		parts = mixed.Bytes()
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", from, strings.Join(to, ","), mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Content-Type: %s\r\n\r\n", contentType)
+	msg.Write(parts)
+	return msg.Bytes(), nil
+}
+
+// buildAlternative 构造 multipart/alternative 正文部分（不含邮件头），返回内容与 boundary。
+func buildAlternative(body mailBody) ([]byte, string, error) {
+	plain := body.Plain
+	if plain == "" {
+		plain = htmlToText(body.HTML)
+	}
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, p := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", plain},
+		{"text/html; charset=UTF-8", body.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := w.Write(base64Lines([]byte(p.content))); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return parts.Bytes(), mw.Boundary(), nil
+}
+
+// writeAttachment 写一个 base64 编码的附件部分。文件名同时放在 Content-Type 的 name 与 Content-Disposition 的 filename，
+// 非 ASCII 文件名由 mime.FormatMediaType 按 RFC 2231 编码，兼顾新旧客户端。
+func writeAttachment(mw *multipart.Writer, a attachment) error {
+	ct := a.ContentType
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	mediaType, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return fmt.Errorf("mail: 附件 %s Content-Type 无效: %w", a.Name, err)
+	}
+	params["name"] = a.Name
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, params)},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(base64Lines(a.Data))
+	return err
+}
+
+// base64Lines base64 编码并按 mimeLineLen 折行（CRLF）。
+func base64Lines(data []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b bytes.Buffer
+	for len(enc) > mimeLineLen {
+		b.WriteString(enc[:mimeLineLen])
+		b.WriteString("\r\n")
+		enc = enc[mimeLineLen:]
+	}
+	b.WriteString(enc)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+var (
+	reHead      = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	reBlockEnd  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|tr|li|table)>`)
+	reCellEnd   = regexp.MustCompile(`(?i)</(td|th)>`)
+	reTag       = regexp.MustCompile(`<[^>]*>`)
+	reBlankLine = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+)
+
+// htmlToText 把邮件 HTML 粗略转为纯文本：块级结束换行、单元格以制表符分隔、去标签并还原实体。
+// 用于未单独提供纯文本正文的邮件（提醒、告警、问候等）。
+func htmlToText(s string) string {
+	s = reHead.ReplaceAllString(s, "")
+	s = reBlockEnd.ReplaceAllString(s, "\n")
+	s = reCellEnd.ReplaceAllString(s, "\t")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = reBlankLine.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s) + "\n"
+}
+
+// buildPlainTable 选股结果的纯文本正文：标题、提示与按列对齐的制表文本，供纯文本客户端与手机通知预览。
+func buildPlainTable(stocks []*model.Stock, opts ReportOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "今日选股结果（%s取前%d）\n%s。\n\n", opts.sortLabel(), opts.topN(), opts.strategySummary())
+	if opts.CallAuction {
+		b.WriteString(callAuctionNotice + "\n\n")
+	}
+	if c := strings.TrimSpace(opts.Comment); c != "" {
+		b.WriteString("点评：" + c + "\n\n")
+	}
+	sel := export.NewSelector(opts.Columns, defaultReportColumns...)
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(sel.Headers(), "\t"))
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		row := make([]string, len(sel))
+		for i, c := range sel {
+			v := c.Text(s)
+			if v == "" {
+				v = emptyCellValue
+			}
+			if c.Key == "name" && s.Cooldown {
+				v += cooldownSuffix
+			}
+			if c.Key == "name" && s.PushedToday {
+				v += repeatSuffix
+			}
+			row[i] = v
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+	b.WriteString("\n本邮件由选股助手自动发送，请勿直接回复。\n")
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Rand 随机源：格言、加油话等随机挑选均经此接口，*rand.Rand 即满足。
+type Rand interface {
+	Intn(n int) int
+}
+
+// globalRand 默认随机源，使用 math/rand 全局函数（并发安全）。
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int { return rand.Intn(n) }
+
+var (
+	randMu  sync.Mutex
+	randSrc Rand = globalRand{}
+)
+
+// SetRand 替换随机源（如 rand.New(rand.NewSource(seed)) 以复现同一序列），nil 恢复默认。
+// 传入的随机源只在内部加锁后使用，无需自身并发安全。
+func SetRand(r Rand) {
+	randMu.Lock()
+	defer randMu.Unlock()
+	if r == nil {
+		r = globalRand{}
+	}
+	randSrc = r
+}
+
+// pick 从 list 中随机取一项，list 为空返回空串。
+func pick(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	randMu.Lock()
+	defer randMu.Unlock()
+	return list[randSrc.Intn(len(list))]
+}
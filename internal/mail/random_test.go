@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+const testSeed = 42
+
+// picks 用当前随机源交替从格言与加油话中抽取 n 次。
+func picks(n int) []string {
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, pick(stockMaxims), pick(greetingCheers))
+	}
+	return out
+}
+
+// TestSetRandReproducible 同一种子两次抽取序列完全一致，且与直接用该种子的 rand.Rand 计算的下标一致。
+func TestSetRandReproducible(t *testing.T) {
+	t.Cleanup(func() { SetRand(nil) })
+
+	SetRand(rand.New(rand.NewSource(testSeed)))
+	first := picks(20)
+	SetRand(rand.New(rand.NewSource(testSeed)))
+	second := picks(20)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("同一种子两次抽取不一致:\n%v\n%v", first, second)
+	}
+
+	r := rand.New(rand.NewSource(testSeed))
+	for i, got := range first {
+		list := stockMaxims
+		if i%2 == 1 {
+			list = greetingCheers
+		}
+		if want := list[r.Intn(len(list))]; got != want {
+			t.Fatalf("第 %d 次抽取 = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestPickEmptyAndReset(t *testing.T) {
+	t.Cleanup(func() { SetRand(nil) })
+	SetRand(rand.New(rand.NewSource(testSeed)))
+	if got := pick(nil); got != "" {
+		t.Errorf("pick(nil) = %q, want empty", got)
+	}
+	SetRand(nil)
+	if _, ok := randSrc.(globalRand); !ok {
+		t.Errorf("SetRand(nil) 后随机源为 %T, want globalRand", randSrc)
+	}
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"errors"
+	"log"
+	netmail "net/mail"
+	"strings"
+)
+
+var errNoRecipients = errors.New("mail: 没有有效的收件人")
+
+// errNoServer 主服务器与备用列表都未配置 SMTP 地址。
+var errNoServer = errors.New("mail: 未配置 SMTP 服务器")
+
+// parseRecipients 解析 To：按逗号或分号拆分，去空、按地址去重（不区分大小写），
+// 校验基本邮箱格式（支持 "姓名 <a@b.com>"），非法项跳过并告警，避免单个坏地址导致 RCPT 失败整封发不出。
+func parseRecipients(to string) []string {
+	fields := strings.FieldsFunc(to, func(r rune) bool { return r == ',' || r == ';' })
+	seen := make(map[string]bool, len(fields))
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		addr, err := netmail.ParseAddress(f)
+		if err != nil || !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@")+1:], ".") {
+			log.Printf("mail: 收件人 %q 格式无效，已跳过", f)
+			continue
+		}
+		key := strings.ToLower(addr.Address)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, addr.Address)
+	}
+	return out
+}
@@ -1,17 +1,20 @@
-// Package mail 按 SMTP 配置发送选股结果 HTML 邮件。
+// Package mail 按 SMTP 配置发送选股结果邮件（multipart/alternative：纯文本 + HTML）。
 package mail
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
-	"math/rand"
 	"net"
 	"net/smtp"
 	"strconv"
 	"strings"
 	"time"
 
+	"stockMaxWin/internal/buildinfo"
+	"stockMaxWin/internal/export"
+	"stockMaxWin/internal/filter"
 	"stockMaxWin/internal/model"
 	"stockMaxWin/internal/trace"
 )
@@ -56,11 +59,35 @@ const (
 	subjectReport       = "今日选股结果"
 	subjectNoSelection  = "选股提醒：本期无入选，请好好工作"
 	subjectStartup      = "选股助手已启动 · 今日大盘"
+	subjectFailure      = "选股程序异常：连续运行失败"
+	subjectTest         = "选股助手 SMTP 测试邮件"
+	subjectMarketGate   = "选股提醒：大盘择时关闸，空仓观望"
+	titleMarketGate     = "空仓观望"
+	titleTest           = "SMTP 测试"
+	titleFailure        = "选股程序异常"
 	titleReport         = "选股结果"
 	titleNoSelection    = "选股提醒"
 	titleStartup        = "选股助手已启动"
 	htmlCharset         = "UTF-8"
-	emptyMainBusiness   = "-"
+	emptyCellValue   = "-"
+	defaultSortLabel    = "按涨幅排序"
+	cooldownSuffix      = "（冷却中）"
+	repeatSuffix        = "（持续入选）"
+	defaultReportTopN   = 10
+)
+
+// 集合竞价时段运行的标注：主题后缀与正文提示
+const (
+	callAuctionSubjectSuffix = "（集合竞价数据）"
+	callAuctionNotice        = "集合竞价数据：本轮运行于 9:15~9:30 集合竞价时段，价格与涨幅为竞价撮合结果，并非连续竞价的真实成交，请谨慎参考。"
+)
+
+// CSV 附件：文件名带日期；开头写 UTF-8 BOM，Excel 直接双击打开时中文不乱码
+const (
+	csvAttachmentPrefix     = "selected-"
+	csvAttachmentDateFormat = "2006-01-02"
+	csvAttachmentType       = "text/csv; charset=UTF-8"
+	utf8BOM                 = "\ufeff"
 )
 
 type SMTPConfig struct {
@@ -70,15 +97,88 @@ type SMTPConfig struct {
 	Password string
 	From     string
 	To       string
+	Theme    Theme        // 邮件主题，零值为浅色默认主题
+	Backups  []SMTPServer // 备用服务器，主服务器（Server 为空时为 Backups[0]）发送失败后依次尝试
+}
+
+// SMTPServer 单个 SMTP 服务器的连接与认证信息；From 为空时沿用 SMTPConfig.From。
+type SMTPServer struct {
+	Server   string
+	Port     int
+	User     string
+	Password string
+	From     string
+}
+
+// servers 按尝试顺序返回可用服务器：主配置在前，备用在后，跳过 Server 为空的项。
+func (s *SMTPConfig) servers() []SMTPServer {
+	list := make([]SMTPServer, 0, 1+len(s.Backups))
+	list = append(list, SMTPServer{Server: s.Server, Port: s.Port, User: s.User, Password: s.Password, From: s.From})
+	list = append(list, s.Backups...)
+	out := list[:0]
+	for _, srv := range list {
+		if strings.TrimSpace(srv.Server) == "" {
+			continue
+		}
+		if srv.From == "" {
+			srv.From = s.From
+		}
+		out = append(out, srv)
+	}
+	return out
+}
+
+// theme 返回渲染用主题：在默认主题上覆盖已配置的字段。
+func (s *SMTPConfig) theme() Theme {
+	return DefaultTheme().Merge(s.Theme)
 }
 
 func (s *SMTPConfig) Enabled() bool {
-	return strings.TrimSpace(s.Server) != "" &&
+	return len(s.servers()) > 0 &&
 		strings.TrimSpace(s.From) != "" &&
 		strings.TrimSpace(s.To) != ""
 }
 
-func SendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock) error {
+// ReportOptions 选股结果邮件的展示选项：排序说明（如“按涨幅排序”）、取前 N 与展示列（export 列 Key，空为默认列）；
+// Comment 为可选的点评文本（如 LLM 生成），空则不展示；CallAuction 为 true 时在标题下提示数据来自集合竞价；
+// StrategySummary 为标题下的策略说明，空则按内置默认阈值描述（filter.DefaultThresholds().Summary()）；
+// CSVAttachment 为 true 时另附 selected-日期.csv，含全部列的完整结果，正文表格仍只展示 Columns。
+type ReportOptions struct {
+	SortLabel       string
+	TopN            int
+	Columns         []string
+	Comment         string
+	CallAuction     bool
+	StrategySummary string
+	CSVAttachment   bool
+}
+
+// defaultReportColumns 邮件表格默认列：代码、名称、现价、涨幅、MA20、MA60、换手、量比、MACD 红柱、最强概念、主营；
+// 嫌宽时用 ReportOptions.Columns（STOCKMAXWIN_MAIL_FIELDS）只选需要的列
+var defaultReportColumns = []string{"code", "name", "price", "change_pct", "ma20", "ma60", "turnover_rate", "volume_ratio", "macd_histogram", "top_concept", "main_business"}
+
+func (o ReportOptions) sortLabel() string {
+	if o.SortLabel == "" {
+		return defaultSortLabel
+	}
+	return o.SortLabel
+}
+
+func (o ReportOptions) strategySummary() string {
+	if o.StrategySummary == "" {
+		return filter.DefaultThresholds().Summary()
+	}
+	return o.StrategySummary
+}
+
+func (o ReportOptions) topN() int {
+	if o.TopN <= 0 {
+		return defaultReportTopN
+	}
+	return o.TopN
+}
+
+func SendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock, opts ReportOptions) error {
 	if cfg == nil || !cfg.Enabled() {
 		return nil
 	}
@@ -86,13 +186,23 @@ func SendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock) err
 		return nil
 	}
 	trace.Log(ctx, "mail: SendReport to=%s count=%d", cfg.To, len(stocks))
-	body := buildHTMLTable(stocks)
-	subject := subjectReport
-	toList := strings.Split(cfg.To, ",")
-	for i := range toList {
-		toList[i] = strings.TrimSpace(toList[i])
+	body := buildHTMLTable(stocks, opts, cfg.theme())
+	subject := subjectReport + " · " + opts.sortLabel()
+	if opts.CallAuction {
+		subject += callAuctionSubjectSuffix
+	}
+	toList := parseRecipients(cfg.To)
+	mb := mailBody{Plain: buildPlainTable(stocks, opts), HTML: body}
+	if opts.CSVAttachment {
+		a, err := csvAttachment(stocks, time.Now())
+		if err != nil {
+			trace.Log(ctx, "mail: 生成 CSV 附件失败，仅发正文 err=%v", err)
+		} else {
+			mb.Attachments = append(mb.Attachments, a)
+			trace.Log(ctx, "mail: 附带 CSV 附件 %s size=%d", a.Name, len(a.Data))
+		}
 	}
-	err := send(cfg, subject, body, toList)
+	err := sendSteps(ctx, cfg, subject, mb, toList, nil)
 	if err != nil {
 		trace.Log(ctx, "mail: send err=%v", err)
 		return err
@@ -101,27 +211,86 @@ func SendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock) err
 	return nil
 }
 
-func buildHTMLTable(stocks []*model.Stock) string {
+// csvAttachment 把完整选股结果（全部导出列）写成 selected-日期.csv 附件。
+func csvAttachment(stocks []*model.Stock, now time.Time) (attachment, error) {
+	var b bytes.Buffer
+	b.WriteString(utf8BOM)
+	if err := export.NewSelector(nil).WriteCSV(&b, stocks); err != nil {
+		return attachment{}, err
+	}
+	return attachment{
+		Name:        csvAttachmentPrefix + now.Format(csvAttachmentDateFormat) + ".csv",
+		ContentType: csvAttachmentType,
+		Data:        b.Bytes(),
+	}, nil
+}
+
+func buildHTMLTable(stocks []*model.Stock, opts ReportOptions, t Theme) string {
 	var b strings.Builder
-	b.WriteString(`<!DOCTYPE html><html><head><meta charset="` + htmlCharset + `"><title>` + titleReport + `</title></head><body>`)
-	b.WriteString(`<h2>今日选股结果（按涨幅排序取前10）</h2><p>剔除ST/退市·市值&gt;50亿·PE 0-60·站上MA20·MA60向上·MACD红柱增或金叉·换手3%-10%·量比&gt;1.2。</p>`)
-	b.WriteString(`<table border="1" cellspacing="0" cellpadding="8" style="border-collapse: collapse; font-size: 14px;">`)
-	b.WriteString(`<thead><tr style="background: #eee;"><th>代码</th><th>名称</th><th>涨幅%</th><th>主营领域</th></tr></thead><tbody>`)
+	b.WriteString(`<!DOCTYPE html><html><head><meta charset="` + htmlCharset + `"><title>` + titleReport + `</title></head><body style="` + t.bodyStyle() + `">`)
+	b.WriteString(`<div style="` + t.cardStyle("960px") + `">`)
+	b.WriteString(fmt.Sprintf(`<h2 style="margin:0 0 8px;color:%s;">今日选股结果（%s取前%d）</h2>`, t.Primary, escapeHTML(opts.sortLabel()), opts.topN()))
+	b.WriteString(`<p style="color:` + t.Muted + `;">` + escapeHTML(opts.strategySummary()) + `。</p>`)
+	if opts.CallAuction {
+		b.WriteString(`<p style="margin:12px 0;padding:10px 14px;border:1px solid ` + t.Up + `;color:` + t.Up + `;">` + callAuctionNotice + `</p>`)
+	}
+	if c := strings.TrimSpace(opts.Comment); c != "" {
+		b.WriteString(`<p style="margin:12px 0;padding:12px 14px;background:` + t.SurfaceAlt + `;border-left:3px solid ` + t.Primary + `;color:` + t.Text + `;">点评：` + escapeHTML(c) + `</p>`)
+	}
+	sel := export.NewSelector(opts.Columns, defaultReportColumns...)
+	b.WriteString(`<table border="1" cellspacing="0" cellpadding="8" style="border-collapse:collapse;font-size:14px;border-color:` + t.Border + `;">`)
+	b.WriteString(`<thead><tr style="background:` + t.SurfaceAlt + `;">`)
+	for _, h := range sel.Headers() {
+		b.WriteString(`<th style="color:` + t.Primary + `;">` + escapeHTML(h) + "</th>")
+	}
+	b.WriteString(`</tr></thead><tbody>`)
 	for _, s := range stocks {
 		if s == nil {
 			continue
 		}
-		mb := s.MainBusiness
-		if mb == "" {
-			mb = emptyMainBusiness
+		b.WriteString("<tr>")
+		for _, c := range sel {
+			v := c.Text(s)
+			if v == "" {
+				v = emptyCellValue
+			}
+			if c.Key == "name" && s.Cooldown {
+				v += cooldownSuffix
+			}
+			if c.Key == "name" && s.PushedToday {
+				v += repeatSuffix
+			}
+			if color := cellColor(c.Key, s, t); color != "" {
+				b.WriteString(`<td style="color:` + color + `;">` + escapeHTML(v) + "</td>")
+				continue
+			}
+			b.WriteString("<td>" + escapeHTML(v) + "</td>")
 		}
-		b.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%.2f</td><td>%s</td></tr>",
-			escapeHTML(s.Code), escapeHTML(s.Name), s.ChangePct, escapeHTML(mb)))
+		b.WriteString("</tr>")
 	}
-	b.WriteString("</tbody></table></body></html>")
+	b.WriteString("</tbody></table>")
+	b.WriteString(footerHTML(t))
+	b.WriteString("</div></body></html>")
 	return b.String()
 }
 
+// cellColor 涨跌类列的字体颜色（红涨绿跌，同启动问候）：现价、涨幅按当日涨跌，MACD 红柱按正负；其他列返回空用默认色。
+func cellColor(key string, s *model.Stock, t Theme) string {
+	switch key {
+	case "price", "change_pct":
+		return t.changeColor(s.ChangePct)
+	case "macd_histogram":
+		return t.changeColor(s.MacdHistogram)
+	}
+	return ""
+}
+
+// footerHTML 邮件页脚：自动发送说明与程序版本，便于确认线上运行的版本。
+func footerHTML(t Theme) string {
+	return `<p style="margin:20px 0 0;font-size:12px;color:` + t.Muted + `;">本邮件由选股助手自动发送，请勿直接回复。版本 ` +
+		escapeHTML(buildinfo.String()) + `</p>`
+}
+
 func escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
 	s = strings.ReplaceAll(s, "<", "&lt;")
@@ -130,7 +299,50 @@ func escapeHTML(s string) string {
 	return s
 }
 
-func send(cfg *SMTPConfig, subject, htmlBody string, to []string) error {
+// send 发送 HTML 邮件，纯文本备选部分由 HTML 转换得到。
+func send(ctx context.Context, cfg *SMTPConfig, subject, htmlBody string, to []string) error {
+	return sendSteps(ctx, cfg, subject, mailBody{HTML: htmlBody}, to, nil)
+}
+
+// StepFunc 发送过程中每完成（或失败）一步回调一次，供 mailtest 逐步打印；err 为 nil 表示该步成功。
+type StepFunc func(step string, err error)
+
+// sendSteps 即 send 的实现：按 cfg.servers() 顺序尝试，某个服务器发送成功即返回，全部失败才返回错误（多个时附上失败数）。
+// 每次尝试与切换都记 trace 日志；step 非 nil 时逐步回报每个服务器的连接、TLS、认证、发件人、收件人、正文、退出。
+func sendSteps(ctx context.Context, cfg *SMTPConfig, subject string, body mailBody, to []string, step StepFunc) error {
+	report := func(name string, err error) error {
+		if step != nil {
+			step(name, err)
+		}
+		return err
+	}
+	if len(to) == 0 {
+		return report("收件人检查", errNoRecipients)
+	}
+	servers := cfg.servers()
+	if len(servers) == 0 {
+		return report("服务器检查", errNoServer)
+	}
+	var lastErr error
+	for i, srv := range servers {
+		if i > 0 {
+			trace.Log(ctx, "mail: SMTP %s 发送失败，切换到备用 %s（%d/%d）", servers[i-1].Server, srv.Server, i+1, len(servers))
+		}
+		trace.Log(ctx, "mail: 尝试 SMTP %s port=%d（%d/%d）", srv.Server, srv.Port, i+1, len(servers))
+		lastErr = sendVia(srv, subject, body, to, report)
+		if lastErr == nil {
+			return nil
+		}
+		trace.Log(ctx, "mail: SMTP %s 失败 err=%v", srv.Server, lastErr)
+	}
+	if len(servers) == 1 {
+		return lastErr
+	}
+	return fmt.Errorf("mail: %d 个 SMTP 服务器全部发送失败，最后一个: %w", len(servers), lastErr)
+}
+
+// sendVia 经单个服务器发送一次，每步结果交给 report。
+func sendVia(cfg SMTPServer, subject string, body mailBody, to []string, report func(string, error) error) error {
 	port := cfg.Port
 	if port == 0 {
 		port = defaultSMTPPort
@@ -145,60 +357,85 @@ func send(cfg *SMTPConfig, subject, htmlBody string, to []string) error {
 		conn, err = net.DialTimeout("tcp", addr, smtpTimeout)
 	}
 	if err != nil {
-		return fmt.Errorf("smtp dial: %w", err)
+		return report("连接 "+addr, fmt.Errorf("smtp dial: %w", err))
 	}
 	defer conn.Close()
+	report("连接 "+addr, nil)
 
 	client, err := smtp.NewClient(conn, cfg.Server)
 	if err != nil {
-		return fmt.Errorf("smtp client: %w", err)
+		return report("SMTP 握手", fmt.Errorf("smtp client: %w", err))
 	}
 	defer client.Close()
+	report("SMTP 握手", nil)
 
 	if port != smtpPortTLS {
 		if ok, _ := client.Extension("STARTTLS"); ok {
 			if err := client.StartTLS(&tls.Config{ServerName: cfg.Server}); err != nil {
-				return fmt.Errorf("starttls: %w", err)
+				return report("STARTTLS", fmt.Errorf("starttls: %w", err))
 			}
+			report("STARTTLS", nil)
 		}
 	}
 
 	if cfg.Password != "" {
 		auth := smtp.PlainAuth("", cfg.User, cfg.Password, cfg.Server)
 		if err := client.Auth(auth); err != nil {
-			return fmt.Errorf("smtp auth: %w", err)
+			return report("认证 "+cfg.User, fmt.Errorf("smtp auth: %w", err))
 		}
+		report("认证 "+cfg.User, nil)
 	}
 
 	if err := client.Mail(cfg.From); err != nil {
-		return fmt.Errorf("smtp mail: %w", err)
+		return report("发件人 "+cfg.From, fmt.Errorf("smtp mail: %w", err))
 	}
+	report("发件人 "+cfg.From, nil)
 	for _, t := range to {
 		if t == "" {
 			continue
 		}
 		if err := client.Rcpt(t); err != nil {
-			return fmt.Errorf("smtp rcpt %s: %w", t, err)
+			return report("收件人 "+t, fmt.Errorf("smtp rcpt %s: %w", t, err))
 		}
+		report("收件人 "+t, nil)
 	}
 
+	msg, err := buildMessage(cfg.From, to, subject, body)
+	if err != nil {
+		return report("写入正文", fmt.Errorf("build message: %w", err))
+	}
 	w, err := client.Data()
 	if err != nil {
-		return fmt.Errorf("smtp data: %w", err)
+		return report("写入正文", fmt.Errorf("smtp data: %w", err))
 	}
-	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n",
-		cfg.From, strings.Join(to, ","), subject)
-	if _, err := w.Write([]byte(headers + htmlBody)); err != nil {
+	if _, err := w.Write(msg); err != nil {
 		_ = w.Close()
-		return fmt.Errorf("smtp write: %w", err)
+		return report("写入正文", fmt.Errorf("smtp write: %w", err))
 	}
 	if err := w.Close(); err != nil {
-		return fmt.Errorf("smtp close: %w", err)
+		return report("写入正文", fmt.Errorf("smtp close: %w", err))
 	}
-	return client.Quit()
+	report("写入正文", nil)
+	return report("QUIT", client.Quit())
 }
 
-func MustSendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock) {
+// SendTestMail 用当前配置发送一封固定内容的测试邮件到 To，逐步回调每一步结果，用于排查 SMTP 配置。
+func SendTestMail(ctx context.Context, cfg *SMTPConfig, step StepFunc) error {
+	if cfg == nil || !cfg.Enabled() {
+		return fmt.Errorf("SMTP 未配置：需要 server、from、to")
+	}
+	trace.Log(ctx, "mail: 发送测试邮件 server=%s port=%d to=%s", cfg.Server, cfg.Port, cfg.To)
+	t := cfg.theme()
+	body := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body style="%s">
+<h2 style="color:%s;">SMTP 配置测试</h2>
+<p>收到这封邮件说明选股助手的 SMTP 配置可用。</p>
+<p style="color:%s;">时间：%s</p>
+</body></html>`, htmlCharset, titleTest, t.bodyStyle(), t.Primary, t.Muted, time.Now().Format("2006-01-02 15:04:05"))
+	toList := parseRecipients(cfg.To)
+	return sendSteps(ctx, cfg, subjectTest, mailBody{HTML: body}, toList, step)
+}
+
+func MustSendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock, opts ReportOptions) {
 	if cfg == nil || !cfg.Enabled() {
 		if len(stocks) == 0 {
 			trace.Log(ctx, "mail: 无选中且未配置 SMTP，跳过")
@@ -209,7 +446,7 @@ func MustSendReport(ctx context.Context, cfg *SMTPConfig, stocks []*model.Stock)
 		trace.Log(ctx, "mail: 无选中股票，按设计不发邮件（正常）")
 		return
 	}
-	if err := SendReport(ctx, cfg, stocks); err != nil {
+	if err := SendReport(ctx, cfg, stocks, opts); err != nil {
 		trace.Log(ctx, "mail: 发送失败 err=%v", err)
 		return
 	}
@@ -221,19 +458,59 @@ func SendNoSelectionReminder(ctx context.Context, cfg *SMTPConfig) error {
 	if cfg == nil || !cfg.Enabled() {
 		return nil
 	}
-	quote := stockMaxims[rand.Intn(len(stockMaxims))]
+	quote := pick(stockMaxims)
 	trace.Log(ctx, "mail: 发送无入选提醒，格言=%s", quote)
-	body := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body>
-<h2>本期没有入选股票</h2>
+	t := cfg.theme()
+	body := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body style="%s">
+<h2 style="color:%s;">本期没有入选股票</h2>
 <p>请好好工作，耐心等待符合条件的机会。</p>
-<p style="margin-top:16px;color:#666;font-style:italic;">%s</p>
-</body></html>`, htmlCharset, titleNoSelection, escapeHTML(quote))
+<p style="margin-top:16px;color:%s;font-style:italic;">%s</p>
+</body></html>`, htmlCharset, titleNoSelection, t.bodyStyle(), t.Primary, t.Muted, escapeHTML(quote))
 	subject := subjectNoSelection
-	toList := strings.Split(cfg.To, ",")
-	for i := range toList {
-		toList[i] = strings.TrimSpace(toList[i])
+	toList := parseRecipients(cfg.To)
+	return send(ctx, cfg, subject, body, toList)
+}
+
+// SendFailureAlert 连续多轮运行失败（如行情拉取失败）时发送异常告警，区别于“无入选”提醒，提示运维排查。
+func SendFailureAlert(ctx context.Context, cfg *SMTPConfig, failedRuns int, lastErr error) error {
+	if cfg == nil || !cfg.Enabled() {
+		return nil
+	}
+	errMsg := "-"
+	if lastErr != nil {
+		errMsg = lastErr.Error()
+	}
+	trace.Log(ctx, "mail: 发送异常告警 failed=%d err=%s", failedRuns, errMsg)
+	t := cfg.theme()
+	body := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body style="%s">
+<h2 style="color:%s;">选股程序连续 %d 轮运行失败</h2>
+<p>这不是“无入选”，而是数据拉取或流程执行失败，请检查网络、接口限流或日志。</p>
+<p>最近一次错误：<code>%s</code></p>
+<p style="color:%s;">时间：%s</p>
+</body></html>`, htmlCharset, titleFailure, t.bodyStyle(), t.Primary, failedRuns, escapeHTML(errMsg), t.Muted, time.Now().Format("2006-01-02 15:04:05"))
+	toList := parseRecipients(cfg.To)
+	return send(ctx, cfg, subjectFailure, body, toList)
+}
+
+// SendMarketGateClosed 大盘择时关闸、本轮跳过选股时发送“空仓观望”通知，reason 为关闸原因，indices 为当前大盘（可为空）。
+func SendMarketGateClosed(ctx context.Context, cfg *SMTPConfig, reason string, indices []model.IndexQuote) error {
+	if cfg == nil || !cfg.Enabled() {
+		return nil
+	}
+	trace.Log(ctx, "mail: 发送空仓观望通知 reason=%s", reason)
+	t := cfg.theme()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body style="%s">
+<h2 style="color:%s;">大盘择时关闸，本轮空仓观望</h2>
+<p>关闸原因：%s</p>
+`, htmlCharset, titleMarketGate, t.bodyStyle(), t.Primary, escapeHTML(reason))
+	for _, q := range indices {
+		fmt.Fprintf(&b, "<p>%s %.2f（%+.2f%%）</p>\n", escapeHTML(q.Name), q.Price, q.ChangePct)
 	}
-	return send(cfg, subject, body, toList)
+	fmt.Fprintf(&b, `<p style="color:%s;">时间：%s</p>
+</body></html>`, t.Muted, time.Now().Format("2006-01-02 15:04:05"))
+	toList := parseRecipients(cfg.To)
+	return send(ctx, cfg, subjectMarketGate, b.String(), toList)
 }
 
 // SendStartupGreeting 启动成功时发送打招呼邮件：今日大盘数据 + 随机一句加油的话。
@@ -241,44 +518,84 @@ func SendStartupGreeting(ctx context.Context, cfg *SMTPConfig, indices []model.I
 	if cfg == nil || !cfg.Enabled() {
 		return nil
 	}
-	cheer := greetingCheers[rand.Intn(len(greetingCheers))]
+	cheer := pick(greetingCheers)
 	trace.Log(ctx, "mail: 发送启动问候 to=%s 加油=%s", cfg.To, cheer)
-	body := buildStartupGreetingHTML(indices, cheer)
-	toList := strings.Split(cfg.To, ",")
-	for i := range toList {
-		toList[i] = strings.TrimSpace(toList[i])
-	}
-	return send(cfg, subjectStartup, body, toList)
+	body := buildStartupGreetingHTML(indices, cheer, cfg.theme())
+	toList := parseRecipients(cfg.To)
+	return send(ctx, cfg, subjectStartup, body, toList)
 }
 
-func buildStartupGreetingHTML(indices []model.IndexQuote, cheer string) string {
+func buildStartupGreetingHTML(indices []model.IndexQuote, cheer string, t Theme) string {
 	var b strings.Builder
-	// 现代邮件风格：窄幅、留白、无衬线字体、涨跌颜色
+	// 现代邮件风格：窄幅、留白、无衬线字体、涨跌颜色；配色与字体取自主题
+	cell := "padding:12px 10px;"
+	th := cell + "color:" + t.Muted + ";font-weight:500;"
 	b.WriteString(`<!DOCTYPE html><html><head><meta charset="` + htmlCharset + `"><meta name="viewport" content="width=device-width,initial-scale=1">`)
-	b.WriteString(`<title>` + titleStartup + `</title></head><body style="margin:0;padding:0;background:#f5f5f5;font-family:-apple-system,BlinkMacSystemFont,Segoe UI,Roboto,Helvetica Neue,sans-serif;">`)
-	b.WriteString(`<div style="max-width:520px;margin:24px auto;padding:28px 24px;background:#fff;border-radius:12px;box-shadow:0 2px 12px rgba(0,0,0,.06);">`)
-	b.WriteString(`<h1 style="margin:0 0 8px;font-size:20px;font-weight:600;color:#1a1a1a;">选股助手已启动</h1>`)
-	b.WriteString(`<p style="margin:0 0 20px;font-size:14px;color:#666;">下面是今日大盘，之后会按 9:15～15:00 每半小时跑一次选股（工作日）。</p>`)
+	b.WriteString(`<title>` + titleStartup + `</title></head><body style="` + t.bodyStyle() + `">`)
+	b.WriteString(`<div style="` + t.cardStyle("520px") + `">`)
+	b.WriteString(`<h1 style="margin:0 0 8px;font-size:20px;font-weight:600;color:` + t.Primary + `;">选股助手已启动</h1>`)
+	b.WriteString(`<p style="margin:0 0 20px;font-size:14px;color:` + t.Muted + `;">下面是今日大盘，之后会按 9:15～15:00 每半小时跑一次选股（工作日）。</p>`)
 	b.WriteString(`<table style="width:100%;border-collapse:collapse;font-size:14px;">`)
-	b.WriteString(`<thead><tr style="border-bottom:2px solid #eee;"><th style="text-align:left;padding:12px 10px;color:#666;font-weight:500;">指数</th><th style="text-align:right;padding:12px 10px;color:#666;font-weight:500;">现价</th><th style="text-align:right;padding:12px 10px;color:#666;font-weight:500;">涨跌幅</th></tr></thead><tbody>`)
+	showTrend := hasIntradayTrend(indices)
+	b.WriteString(`<thead><tr style="border-bottom:2px solid ` + t.Border + `;"><th style="text-align:left;` + th + `">指数</th><th style="text-align:right;` + th + `">现价</th><th style="text-align:right;` + th + `">涨跌幅</th>`)
+	if showTrend {
+		b.WriteString(`<th style="text-align:right;` + th + `">相对开盘</th>`)
+	}
+	b.WriteString(`</tr></thead><tbody>`)
 	for i, q := range indices {
-		bg := "#fff"
+		bg := t.Surface
 		if i%2 == 1 {
-			bg = "#fafafa"
-		}
-		pctStyle := "color:#333;"
-		if q.ChangePct > 0 {
-			pctStyle = "color:#c62828;"
-		} else if q.ChangePct < 0 {
-			pctStyle = "color:#2e7d32;"
+			bg = t.SurfaceAlt
 		}
 		pctStr := fmt.Sprintf("%.2f%%", q.ChangePct)
-		b.WriteString(fmt.Sprintf(`<tr style="background:%s"><td style="padding:12px 10px;color:#1a1a1a;">%s</td><td style="text-align:right;padding:12px 10px;color:#1a1a1a;">%.2f</td><td style="text-align:right;padding:12px 10px;%s">%s</td></tr>`,
-			bg, escapeHTML(q.Name), q.Price, pctStyle, pctStr))
+		b.WriteString(fmt.Sprintf(`<tr style="background:%s"><td style="%scolor:%s;">%s</td><td style="text-align:right;%scolor:%s;">%.2f</td><td style="text-align:right;%scolor:%s;">%s</td>`,
+			bg, cell, t.Text, escapeHTML(q.Name), cell, t.Text, q.Price, cell, t.changeColor(q.ChangePct), pctStr))
+		if showTrend {
+			trend, color := "-", t.Muted
+			if pct, ok := q.SinceOpenPct(); ok {
+				trend, color = intradayTrendLabel(q), t.changeColor(pct)
+			}
+			b.WriteString(fmt.Sprintf(`<td style="text-align:right;%scolor:%s;">%s</td>`, cell, color, trend))
+		}
+		b.WriteString(`</tr>`)
 	}
 	b.WriteString("</tbody></table>")
-	b.WriteString(`<p style="margin:22px 0 0;padding:14px 16px;background:#f8f9fa;border-radius:8px;font-size:14px;color:#374151;line-height:1.5;">` + escapeHTML(cheer) + `</p>`)
-	b.WriteString(`<p style="margin:20px 0 0;font-size:12px;color:#9ca3af;">本邮件由选股助手自动发送，请勿直接回复。</p>`)
+	b.WriteString(`<p style="margin:22px 0 0;padding:14px 16px;background:` + t.SurfaceAlt + `;border-radius:8px;font-size:14px;color:` + t.Text + `;line-height:1.5;">` + escapeHTML(cheer) + `</p>`)
+	b.WriteString(footerHTML(t))
 	b.WriteString(`</div></body></html>`)
 	return b.String()
 }
+
+// hasIntradayTrend 至少一个指数有开盘价时才展示“相对开盘”列，接口未返回时整列省略。
+func hasIntradayTrend(indices []model.IndexQuote) bool {
+	for _, q := range indices {
+		if _, ok := q.SinceOpenPct(); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// intradayTrendLabel 形如“高开低走 -0.80%”：开盘相对昨收定高开/低开/平开（缺昨收时省略），现价相对开盘定走势。
+func intradayTrendLabel(q model.IndexQuote) string {
+	since, _ := q.SinceOpenPct()
+	walk := "走平"
+	switch {
+	case since > 0:
+		walk = "高走"
+	case since < 0:
+		walk = "低走"
+	}
+	open := ""
+	if gap, ok := q.OpenGapPct(); ok {
+		switch {
+		case gap > 0:
+			open = "高开"
+		case gap < 0:
+			open = "低开"
+		default:
+			open = "平开"
+		}
+	}
+	return fmt.Sprintf("%s%s %+.2f%%", open, walk, since)
+}
@@ -0,0 +1,113 @@
+package mail
+
+import "strings"
+
+// 内置主题名
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
+// Theme 邮件配色与字体，选股结果与启动问候等模板共用；渲染时从 SMTPConfig 注入。
+// A 股习惯红涨绿跌，品牌色可只改 Primary。
+type Theme struct {
+	Primary    string // 主色：标题、表头强调
+	Up         string // 涨色
+	Down       string // 跌色
+	Background string // 页面背景
+	Surface    string // 卡片/表格背景
+	SurfaceAlt string // 斑马纹行背景
+	Text       string // 正文
+	Muted      string // 次要文字
+	Border     string // 分隔线
+	Font       string // font-family
+	Radius     string // 卡片圆角
+}
+
+const defaultFontFamily = "-apple-system,BlinkMacSystemFont,Segoe UI,Roboto,Helvetica Neue,sans-serif"
+
+// DefaultTheme 浅色主题（与原先内联样式一致）。
+func DefaultTheme() Theme {
+	return Theme{
+		Primary:    "#1a1a1a",
+		Up:         "#c62828",
+		Down:       "#2e7d32",
+		Background: "#f5f5f5",
+		Surface:    "#fff",
+		SurfaceAlt: "#fafafa",
+		Text:       "#1a1a1a",
+		Muted:      "#666",
+		Border:     "#eee",
+		Font:       defaultFontFamily,
+		Radius:     "12px",
+	}
+}
+
+// DarkTheme 暗色主题。
+func DarkTheme() Theme {
+	return Theme{
+		Primary:    "#e5e7eb",
+		Up:         "#ef5350",
+		Down:       "#66bb6a",
+		Background: "#111827",
+		Surface:    "#1f2937",
+		SurfaceAlt: "#273244",
+		Text:       "#e5e7eb",
+		Muted:      "#9ca3af",
+		Border:     "#374151",
+		Font:       defaultFontFamily,
+		Radius:     "12px",
+	}
+}
+
+// ThemeByName 按名返回内置主题，未知名回退浅色。
+func ThemeByName(name string) Theme {
+	if strings.EqualFold(strings.TrimSpace(name), ThemeDark) {
+		return DarkTheme()
+	}
+	return DefaultTheme()
+}
+
+// Merge 用 o 中非空字段覆盖 t，便于在内置主题上只改品牌色等个别项。
+func (t Theme) Merge(o Theme) Theme {
+	pick := func(dst *string, v string) {
+		if strings.TrimSpace(v) != "" {
+			*dst = v
+		}
+	}
+	pick(&t.Primary, o.Primary)
+	pick(&t.Up, o.Up)
+	pick(&t.Down, o.Down)
+	pick(&t.Background, o.Background)
+	pick(&t.Surface, o.Surface)
+	pick(&t.SurfaceAlt, o.SurfaceAlt)
+	pick(&t.Text, o.Text)
+	pick(&t.Muted, o.Muted)
+	pick(&t.Border, o.Border)
+	pick(&t.Font, o.Font)
+	pick(&t.Radius, o.Radius)
+	return t
+}
+
+// changeColor 按涨跌返回颜色：涨用 Up，跌用 Down，平用正文色。
+func (t Theme) changeColor(pct float64) string {
+	switch {
+	case pct > 0:
+		return t.Up
+	case pct < 0:
+		return t.Down
+	default:
+		return t.Text
+	}
+}
+
+// bodyStyle 页面 body 的公共样式。
+func (t Theme) bodyStyle() string {
+	return "margin:0;padding:0;background:" + t.Background + ";font-family:" + t.Font + ";color:" + t.Text + ";"
+}
+
+// cardStyle 内容卡片样式。
+func (t Theme) cardStyle(maxWidth string) string {
+	return "max-width:" + maxWidth + ";margin:24px auto;padding:28px 24px;background:" + t.Surface +
+		";border-radius:" + t.Radius + ";box-shadow:0 2px 12px rgba(0,0,0,.06);"
+}
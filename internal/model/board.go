@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board A 股板块。
+type Board string
+
+// 板块：主板（沪 60/5、深 00）、创业板（300/301）、科创板（688/689）、北交所（8/4/92）
+const (
+	BoardMain    Board = "main"
+	BoardChiNext Board = "chinext"
+	BoardSTAR    Board = "star"
+	BoardBSE     Board = "bse"
+)
+
+// Label 板块中文名，用于日志与报告。
+func (b Board) Label() string {
+	switch b {
+	case BoardMain:
+		return "主板"
+	case BoardChiNext:
+		return "创业板"
+	case BoardSTAR:
+		return "科创板"
+	case BoardBSE:
+		return "北交所"
+	}
+	return string(b)
+}
+
+// BoardOf 按代码前缀判断所属板块，无法识别返回空串。
+func BoardOf(code string) Board {
+	code = strings.TrimSpace(code)
+	switch {
+	case len(code) < 2:
+		return ""
+	case strings.HasPrefix(code, "300"), strings.HasPrefix(code, "301"):
+		return BoardChiNext
+	case strings.HasPrefix(code, "688"), strings.HasPrefix(code, "689"):
+		return BoardSTAR
+	case code[0] == '8', code[0] == '4', strings.HasPrefix(code, "92"):
+		return BoardBSE
+	case code[0] == '6', code[0] == '5', strings.HasPrefix(code, "00"):
+		return BoardMain
+	}
+	return ""
+}
+
+// ParseBoards 解析逗号分隔的板块名（main/chinext/star/bse，大小写不敏感），去重保序；含未知名时返回错误。
+func ParseBoards(s string) ([]Board, error) {
+	var out []Board
+	seen := make(map[Board]bool)
+	for _, part := range strings.Split(s, ",") {
+		b := Board(strings.ToLower(strings.TrimSpace(part)))
+		if b == "" {
+			continue
+		}
+		switch b {
+		case BoardMain, BoardChiNext, BoardSTAR, BoardBSE:
+		default:
+			return nil, fmt.Errorf("unknown board %q", part)
+		}
+		if !seen[b] {
+			seen[b] = true
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
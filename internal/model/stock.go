@@ -3,64 +3,165 @@ package model
 
 // Stock 选股结果：行情 + K 线均线 + 市值/PE + MACD 等，供过滤与邮件展示。
 type Stock struct {
-	Code             string
-	Name             string
-	MainBusiness     string
-	Price            float64
-	MA5              float64
-	MA10             float64
-	MA20             float64
-	MA60             float64
-	ChangePct        float64
-	Amount           float64
-	VolumeRatio      float64
-	TurnoverRate     float64
-	MarketCap        float64 // 总市值(元)
-	PE               float64 // 市盈率，无效或负为 0
-	NetInflow        float64
-	MainForceInflow  float64
-	MainForceOutflow float64
-	MA60Up           bool    // MA60 相对 5 日前向上
-	MacdHistogram    float64 // 当日 MACD 红柱
-	MacdHistogramPrev float64 // 昨日 MACD 红柱
-	MacdGoldenCross  bool    // 近两日发生低位金叉
+	Code                string  `json:"code"`
+	Name                string  `json:"name"`
+	MainBusiness        string  `json:"main_business"`
+	Price               float64 `json:"price"`
+	MA5                 float64 `json:"ma5"`
+	MA10                float64 `json:"ma10"`
+	MA20                float64 `json:"ma20"`
+	MA60                float64 `json:"ma60"`
+	MA5Valid            bool    `json:"ma5_valid"` // 对应均线样本充足（K 线根数 >= 周期）；不足时均线值为 0，不可参与比较
+	MA10Valid           bool    `json:"ma10_valid"`
+	MA20Valid           bool    `json:"ma20_valid"`
+	MA60Valid           bool    `json:"ma60_valid"`
+	ChangePct           float64 `json:"change_pct"`
+	Amount              float64 `json:"amount"`
+	VolumeRatio         float64 `json:"volume_ratio"`
+	TurnoverRate        float64 `json:"turnover_rate"`
+	MarketCap           float64 `json:"market_cap"` // 总市值(元)
+	PE                  float64 `json:"pe"`         // 市盈率，无效或负为 0
+	NetInflow           float64 `json:"net_inflow"`
+	MainForceInflow     float64 `json:"main_force_inflow"`
+	MainForceOutflow    float64 `json:"main_force_outflow"`
+	MoneyFlowMissing    bool    `json:"money_flow_missing"`     // 主力资金数据缺失：列表字段为空且资金流接口未取到（或未开启），资金条件此时放行
+	MA60Up              bool    `json:"ma60_up"`                // MA60 相对 5 日前向上
+	MA20CrossUpMA60     bool    `json:"ma20_cross_up_ma60"`     // MA20 当日上穿 MA60：昨日 MA20<=MA60，今日 MA20>MA60
+	MacdHistogram       float64 `json:"macd_histogram"`         // 当日 MACD 红柱
+	MacdHistogramPrev   float64 `json:"macd_histogram_prev"`    // 昨日 MACD 红柱
+	MacdGoldenCross     bool    `json:"macd_golden_cross"`      // 近两日发生低位金叉
+	RSI14               float64 `json:"rsi14"`                  // 14 日 RSI（Wilder 平滑），K 线不足为 0
+	BollUpper           float64 `json:"boll_upper"`             // 20 日布林带上轨（MA20 + 2 倍收盘价样本标准差），K 线不足为 0
+	BollMid             float64 `json:"boll_mid"`               // 20 日布林带中轨（即 MA20），K 线不足为 0
+	BollLower           float64 `json:"boll_lower"`             // 20 日布林带下轨（MA20 - 2 倍收盘价样本标准差），K 线不足为 0
+	ATR14               float64 `json:"atr14"`                  // 14 日 ATR（真实波幅 Wilder 平滑），K 线不足为 0
+	ATRPct              float64 `json:"atr_pct"`                // ATR14 / 现价(%)，日均波动幅度，K 线不足为 0
+	UpStreak            int     `json:"up_streak"`              // 截至最新 K 线连续上涨（收盘价逐日走高）天数
+	NewHighDays         int     `json:"new_high_days"`          // 最新收盘价为近多少日（含当日）收盘新高，受 K 线根数限制
+	IsNewHigh           bool    `json:"is_new_high"`            // 最新收盘价为近 60 日收盘新高
+	PB                  float64 `json:"pb"`                     // 市净率，缺失为 0
+	ROE                 float64 `json:"roe"`                    // 净资产收益率(%)，缺失为 0
+	RevenueGrowth       float64 `json:"revenue_growth"`         // 营收同比增速(%)，缺失为 0
+	ProfitGrowth        float64 `json:"profit_growth"`          // 净利润同比增速(%)，缺失为 0
+	Industry            string  `json:"industry"`               // 所属行业
+	IndustryChangePct   float64 `json:"industry_change_pct"`    // 所属行业当日涨幅(%)
+	IndustryRank        int     `json:"industry_rank"`          // 所属行业当日涨幅排名，从 1 开始，0 表示未知
+	Cooldown            bool    `json:"cooldown"`               // 冷却期内已推送过（标注模式下仍展示）
+	PushedToday         bool    `json:"pushed_today"`           // 当日此前已推送过（持续入选），去重标注模式下展示
+	Delivered           bool    `json:"delivered"`              // 本轮至少经一个推送渠道送达，冷却期只按送达过的记录计算
+	SuspendedDays       int     `json:"suspended_days"`         // K 线窗口内停牌日（成交量为 0）天数
+	Volume              int64   `json:"volume"`                 // 当日（最新一根 K 线）成交量(手)
+	VolMA5              float64 `json:"vol_ma5"`                // 当日之前 5 日均量(手)，默认剔除停牌日，不足 5 日为 0
+	HighN               float64 `json:"high_n"`                 // 近 60 日最高收盘价（含现价），数据不足为 0
+	DrawdownFromHigh    float64 `json:"drawdown_from_high"`     // 现价相对 HighN 的回调幅度(%)，数据不足为 0
+	TopConcept          string  `json:"top_concept"`            // 所属概念中当日涨幅最高的一个，数据缺失为空
+	TopConceptChangePct float64 `json:"top_concept_change_pct"` // TopConcept 当日涨幅(%)
+	PriceDeviationPct   float64 `json:"price_deviation_pct"`    // 现价相对最新 K 线收盘价的偏差(%)，偏大说明行情与 K 线不同步
+	PreFilterRank       int     `json:"pre_filter_rank"`        // 初选候选中按强度分的排名，从 1 开始，0 表示未标注
+	FundFlowDays        int     `json:"fund_flow_days"`         // 已拉到的日资金流天数，0 表示未拉取或无数据
+	MainNetInflowSum    float64 `json:"main_net_inflow_sum"`    // 近 FundFlowDays 日主力净流入之和(元)
+	MainNetInflowStreak int     `json:"main_net_inflow_streak"` // 截至最近交易日连续主力净流入天数
+	Score               float64 `json:"score"`                  // 多因子评分（worker.Config.Scorer），未打分为 0
 }
 
 // StockQuote 列表接口单条：代码、名称、现价、涨跌幅、成交额、量比、换手、市值、PE 等。
 type StockQuote struct {
-	Code             string
-	Name             string
-	MainBusiness     string
-	Price            float64
-	ChangePct        float64
-	Amount           float64
-	VolumeRatio      float64
-	TurnoverRate     float64
-	MarketCap        float64
-	PE               float64
-	NetInflow        float64
-	MainForceInflow  float64
-	MainForceOutflow float64
+	Code                string   `json:"code"`
+	Name                string   `json:"name"`
+	MainBusiness        string   `json:"main_business"`
+	Price               float64  `json:"price"`
+	ChangePct           float64  `json:"change_pct"`
+	Amount              float64  `json:"amount"`
+	VolumeRatio         float64  `json:"volume_ratio"`
+	TurnoverRate        float64  `json:"turnover_rate"`
+	MarketCap           float64  `json:"market_cap"`
+	PE                  float64  `json:"pe"`
+	NetInflow           float64  `json:"net_inflow"`
+	MainForceInflow     float64  `json:"main_force_inflow"`
+	MainForceOutflow    float64  `json:"main_force_outflow"`
+	PB                  float64  `json:"pb"`
+	ROE                 float64  `json:"roe"`
+	RevenueGrowth       float64  `json:"revenue_growth"`
+	ProfitGrowth        float64  `json:"profit_growth"`
+	Industry            string   `json:"industry"`
+	IndustryChangePct   float64  `json:"industry_change_pct"`
+	IndustryRank        int      `json:"industry_rank"`
+	Concepts            []string `json:"concepts"` // 所属概念板块名称（列表接口 f103）
+	TopConcept          string   `json:"top_concept"`
+	TopConceptChangePct float64  `json:"top_concept_change_pct"`
+	PreFilterRank       int      `json:"pre_filter_rank"`
 }
 
 // StockBrief 仅代码与名称，用于全市场列表等。
 type StockBrief struct {
-	Code string
-	Name string
+	Code string `json:"code"`
+	Name string `json:"name"`
 }
 
-// KLine 单日 K：日期、开收、成交量。
+// KLine 单日 K：日期、开收、最高最低、成交量。
 type KLine struct {
-	Date   string
-	Close  float64
-	Open   float64
-	Volume int64
+	Date   string  `json:"date"`
+	Close  float64 `json:"close"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Volume int64   `json:"volume"`
+}
+
+// MoneyFlow 个股当日主力资金流（资金流专用接口）：主力流入、流出与净流入(元)。
+type MoneyFlow struct {
+	Code          string  `json:"code"`
+	MainInflow    float64 `json:"main_inflow"`
+	MainOutflow   float64 `json:"main_outflow"`
+	MainNetInflow float64 `json:"main_net_inflow"`
+}
+
+// FundFlowDay 个股单日资金流：日期与主力净流入(元)。
+type FundFlowDay struct {
+	Date          string  `json:"date"`
+	MainNetInflow float64 `json:"main_net_inflow"`
 }
 
 // IndexQuote 大盘指数一条：名称、代码、现价、涨跌幅（用于启动问候邮件）。
 type IndexQuote struct {
-	Code      string
-	Name      string
-	Price     float64
-	ChangePct float64
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	ChangePct float64 `json:"change_pct"`
+	Open      float64 `json:"open"`       // 今开，接口未返回或未开盘时为 0
+	PrevClose float64 `json:"prev_close"` // 昨收，接口未返回时为 0
 }
+
+// OpenGapPct 开盘相对昨收的涨跌幅(%)，开盘价或昨收缺失时 ok=false。
+func (q IndexQuote) OpenGapPct() (pct float64, ok bool) {
+	if q.Open <= 0 || q.PrevClose <= 0 {
+		return 0, false
+	}
+	return (q.Open - q.PrevClose) / q.PrevClose * 100, true
+}
+
+// SinceOpenPct 现价相对开盘的涨跌幅(%)，开盘价缺失时 ok=false。
+func (q IndexQuote) SinceOpenPct() (pct float64, ok bool) {
+	if q.Open <= 0 || q.Price <= 0 {
+		return 0, false
+	}
+	return (q.Price - q.Open) / q.Open * 100, true
+}
+
+// StockProfile 公司概况中用于展示的字段：所属行业与主营业务。
+type StockProfile struct {
+	Code         string `json:"code"`
+	Industry     string `json:"industry"`
+	MainBusiness string `json:"main_business"`
+}
+
+// IndustryBoard 行业板块当日行情：代码、名称、涨跌幅及涨幅排名（从 1 开始）。
+type IndustryBoard struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	ChangePct float64 `json:"change_pct"`
+	Rank      int     `json:"rank"`
+}
+
+// ConceptBoard 概念板块当日行情，字段与行业板块相同。
+type ConceptBoard = IndustryBoard
@@ -0,0 +1,130 @@
+// Package notify 提供邮件以外的选股结果推送渠道，各渠道实现 Notifier。
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 推送请求超时与 markdown 展示
+const (
+	httpTimeout     = 10 * time.Second
+	reportTitle     = "今日选股结果"
+	emptyFieldValue = "-"
+	cooldownSuffix  = "（冷却中）"
+	repeatSuffix    = "（持续入选）"
+)
+
+// Notifier 推送渠道：把本轮入选股票发出去。
+type Notifier interface {
+	Name() string
+	SendReport(ctx context.Context, stocks []*model.Stock) error
+}
+
+// SendAll 依次调用各渠道，单个渠道失败只记日志，不影响其余渠道；返回发送成功的渠道数，
+// 调用方据此判断是否已送达（如仅在至少一个渠道成功时才记为今日已推送）。
+func SendAll(ctx context.Context, notifiers []Notifier, stocks []*model.Stock) (delivered int) {
+	if len(stocks) == 0 {
+		return 0
+	}
+	for _, n := range notifiers {
+		if n == nil {
+			continue
+		}
+		if err := n.SendReport(ctx, stocks); err != nil {
+			trace.Log(ctx, "notify: %s 发送失败 err=%v", n.Name(), err)
+			continue
+		}
+		trace.Log(ctx, "notify: %s 已发送 count=%d", n.Name(), len(stocks))
+		delivered++
+	}
+	return delivered
+}
+
+// buildMarkdown 生成各渠道通用的 markdown 列表：代码、名称、涨幅、现价。
+func buildMarkdown(stocks []*model.Stock) string {
+	var b strings.Builder
+	b.WriteString("### " + reportTitle + "\n")
+	for _, line := range markdownLines(stocks) {
+		b.WriteString("> " + line + "\n")
+	}
+	return b.String()
+}
+
+// markdownLines 每只一行的 markdown（不含标题与引用符），供卡片类渠道自行排版。
+func markdownLines(stocks []*model.Stock) []string {
+	lines := make([]string, 0, len(stocks))
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		name := s.Name
+		if name == "" {
+			name = emptyFieldValue
+		}
+		if s.Cooldown {
+			name += cooldownSuffix
+		}
+		if s.PushedToday {
+			name += repeatSuffix
+		}
+		lines = append(lines, fmt.Sprintf("**%s %s** 涨幅 %.2f%% 现价 %.2f", s.Code, name, s.ChangePct, s.Price))
+	}
+	return lines
+}
+
+// postJSON 以 JSON POST 到 url，并把响应解析到 out（out 为 nil 时忽略响应体）。
+func postJSON(ctx context.Context, client *http.Client, url string, payload, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return doJSON(client, req, out)
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(client, req, out)
+}
+
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/model"
+)
+
+// WebhookConfig 群机器人 Webhook：地址与可选的加签密钥（飞书、钉钉“加签”安全设置；企业微信群机器人无密钥）。
+type WebhookConfig struct {
+	URL       string
+	Secret    string
+	Transport http.RoundTripper // 可选，用于按域名走代理；nil 为默认 Transport
+}
+
+func (c *WebhookConfig) Enabled() bool {
+	return c != nil && strings.TrimSpace(c.URL) != ""
+}
+
+// webhookResp 各机器人接口的响应：飞书为 code/msg，钉钉与企业微信为 errcode/errmsg，成功均为 0。
+type webhookResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (r webhookResp) err(name string) error {
+	if r.Code != 0 {
+		return fmt.Errorf("%s code=%d msg=%s", name, r.Code, r.Msg)
+	}
+	if r.ErrCode != 0 {
+		return fmt.Errorf("%s errcode=%d errmsg=%s", name, r.ErrCode, r.ErrMsg)
+	}
+	return nil
+}
+
+func postWebhook(ctx context.Context, client *http.Client, name, u string, payload interface{}) error {
+	var r webhookResp
+	if err := postJSON(ctx, client, u, payload, &r); err != nil {
+		return fmt.Errorf("%s send: %w", name, err)
+	}
+	return r.err(name)
+}
+
+// hmacBase64 HMAC-SHA256 后 base64 编码，飞书与钉钉加签共用。
+func hmacBase64(key, msg string) string {
+	m := hmac.New(sha256.New, []byte(key))
+	m.Write([]byte(msg))
+	return base64.StdEncoding.EncodeToString(m.Sum(nil))
+}
+
+// FeishuNotifier 飞书群机器人：发交互卡片，标题为报告标题，正文为 markdown 列表。
+type FeishuNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func NewFeishuNotifier(cfg WebhookConfig) *FeishuNotifier {
+	return &FeishuNotifier{cfg: cfg, client: &http.Client{Timeout: httpTimeout, Transport: cfg.Transport}}
+}
+
+func (n *FeishuNotifier) Name() string { return "feishu" }
+
+func (n *FeishuNotifier) SendReport(ctx context.Context, stocks []*model.Stock) error {
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title": map[string]string{"tag": "plain_text", "content": reportTitle},
+			},
+			"elements": []map[string]string{
+				{"tag": "markdown", "content": strings.Join(markdownLines(stocks), "\n")},
+			},
+		},
+	}
+	if n.cfg.Secret != "" {
+		// 飞书加签：以 timestamp+"\n"+secret 为密钥对空串做 HMAC-SHA256
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = ts
+		payload["sign"] = hmacBase64(ts+"\n"+n.cfg.Secret, "")
+	}
+	return postWebhook(ctx, n.client, n.Name(), n.cfg.URL, payload)
+}
+
+// DingTalkNotifier 钉钉群机器人：发 markdown 消息；配置了加签密钥时在 URL 上附 timestamp 与 sign。
+type DingTalkNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func NewDingTalkNotifier(cfg WebhookConfig) *DingTalkNotifier {
+	return &DingTalkNotifier{cfg: cfg, client: &http.Client{Timeout: httpTimeout, Transport: cfg.Transport}}
+}
+
+func (n *DingTalkNotifier) Name() string { return "dingtalk" }
+
+func (n *DingTalkNotifier) SendReport(ctx context.Context, stocks []*model.Stock) error {
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": reportTitle,
+			// 钉钉 markdown 需用空行分段，单个换行不生效
+			"text": "### " + reportTitle + "\n\n" + strings.Join(markdownLines(stocks), "\n\n"),
+		},
+	}
+	u := n.cfg.URL
+	if n.cfg.Secret != "" {
+		// 钉钉加签：以 secret 为密钥对 毫秒时间戳+"\n"+secret 做 HMAC-SHA256
+		ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		sep := "?"
+		if strings.Contains(u, "?") {
+			sep = "&"
+		}
+		u += sep + "timestamp=" + ts + "&sign=" + url.QueryEscape(hmacBase64(n.cfg.Secret, ts+"\n"+n.cfg.Secret))
+	}
+	return postWebhook(ctx, n.client, n.Name(), u, payload)
+}
+
+// WeChatWorkNotifier 企业微信群机器人（区别于 WeComAppNotifier 的应用消息）：发 markdown 消息，无需 access_token。
+type WeChatWorkNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func NewWeChatWorkNotifier(cfg WebhookConfig) *WeChatWorkNotifier {
+	return &WeChatWorkNotifier{cfg: cfg, client: &http.Client{Timeout: httpTimeout, Transport: cfg.Transport}}
+}
+
+func (n *WeChatWorkNotifier) Name() string { return "wecom_webhook" }
+
+func (n *WeChatWorkNotifier) SendReport(ctx context.Context, stocks []*model.Stock) error {
+	payload := map[string]interface{}{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": buildMarkdown(stocks)},
+	}
+	return postWebhook(ctx, n.client, n.Name(), n.cfg.URL, payload)
+}
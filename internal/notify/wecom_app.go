@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 企业微信应用消息接口
+const (
+	weComTokenURL = "https://qyapi.weixin.qq.com/cgi-bin/gettoken"
+	weComSendURL  = "https://qyapi.weixin.qq.com/cgi-bin/message/send"
+)
+
+// 企业微信错误码：access_token 无效 / 过期，需要刷新后重发
+const (
+	weComErrInvalidToken = 40014
+	weComErrTokenExpired = 42001
+)
+
+// 消息类型与 token 提前刷新余量
+const (
+	WeComMsgMarkdown    = "markdown"
+	WeComMsgTextCard    = "textcard"
+	weComTokenEarly     = 5 * time.Minute
+	weComDefaultToUser  = "@all"
+	weComTextCardURL    = "https://quote.eastmoney.com/"
+	weComTextCardButton = "查看行情"
+)
+
+// WeComAppConfig 企业微信应用配置：corpid/corpsecret/agentid 与接收成员（多个用 | 分隔）。
+type WeComAppConfig struct {
+	CorpID     string
+	CorpSecret string
+	AgentID    int
+	ToUser     string
+	MsgType    string            // markdown（默认）或 textcard
+	Transport  http.RoundTripper // 可选，用于按域名走代理；nil 为默认 Transport
+}
+
+func (c *WeComAppConfig) Enabled() bool {
+	return c != nil && strings.TrimSpace(c.CorpID) != "" && strings.TrimSpace(c.CorpSecret) != "" && c.AgentID > 0
+}
+
+// WeComAppNotifier 通过企业微信应用消息推送，自动缓存并刷新 access_token。
+type WeComAppNotifier struct {
+	cfg    WeComAppConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewWeComAppNotifier(cfg WeComAppConfig) *WeComAppNotifier {
+	if cfg.MsgType != WeComMsgTextCard {
+		cfg.MsgType = WeComMsgMarkdown
+	}
+	if strings.TrimSpace(cfg.ToUser) == "" {
+		cfg.ToUser = weComDefaultToUser
+	}
+	return &WeComAppNotifier{cfg: cfg, client: &http.Client{Timeout: httpTimeout, Transport: cfg.Transport}}
+}
+
+func (n *WeComAppNotifier) Name() string { return "wecom_app" }
+
+type weComResp struct {
+	ErrCode     int    `json:"errcode"`
+	ErrMsg      string `json:"errmsg"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// accessToken 返回缓存的 token；过期（含提前余量）或 force 时重新获取。
+func (n *WeComAppNotifier) accessToken(ctx context.Context, force bool) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !force && n.token != "" && time.Now().Before(n.expiresAt) {
+		return n.token, nil
+	}
+	u := fmt.Sprintf("%s?corpid=%s&corpsecret=%s", weComTokenURL,
+		url.QueryEscape(n.cfg.CorpID), url.QueryEscape(n.cfg.CorpSecret))
+	var r weComResp
+	if err := getJSON(ctx, n.client, u, &r); err != nil {
+		return "", fmt.Errorf("wecom gettoken: %w", err)
+	}
+	if r.ErrCode != 0 || r.AccessToken == "" {
+		return "", fmt.Errorf("wecom gettoken errcode=%d errmsg=%s", r.ErrCode, r.ErrMsg)
+	}
+	n.token = r.AccessToken
+	n.expiresAt = time.Now().Add(time.Duration(r.ExpiresIn)*time.Second - weComTokenEarly)
+	trace.Log(ctx, "notify: wecom access_token 已刷新 expires_in=%ds", r.ExpiresIn)
+	return n.token, nil
+}
+
+func (n *WeComAppNotifier) SendReport(ctx context.Context, stocks []*model.Stock) error {
+	payload := n.buildPayload(stocks)
+	for attempt := 0; attempt < 2; attempt++ {
+		token, err := n.accessToken(ctx, attempt > 0)
+		if err != nil {
+			return err
+		}
+		var r weComResp
+		if err := postJSON(ctx, n.client, weComSendURL+"?access_token="+url.QueryEscape(token), payload, &r); err != nil {
+			return fmt.Errorf("wecom send: %w", err)
+		}
+		if r.ErrCode == weComErrInvalidToken || r.ErrCode == weComErrTokenExpired {
+			trace.Log(ctx, "notify: wecom token 失效 errcode=%d，刷新后重发", r.ErrCode)
+			continue
+		}
+		if r.ErrCode != 0 {
+			return fmt.Errorf("wecom send errcode=%d errmsg=%s", r.ErrCode, r.ErrMsg)
+		}
+		return nil
+	}
+	return fmt.Errorf("wecom send: access_token 刷新后仍无效")
+}
+
+func (n *WeComAppNotifier) buildPayload(stocks []*model.Stock) map[string]interface{} {
+	payload := map[string]interface{}{
+		"touser":  n.cfg.ToUser,
+		"msgtype": n.cfg.MsgType,
+		"agentid": n.cfg.AgentID,
+	}
+	if n.cfg.MsgType == WeComMsgTextCard {
+		payload["textcard"] = map[string]string{
+			"title":       reportTitle,
+			"description": buildTextCardDescription(stocks),
+			"url":         weComTextCardURL,
+			"btntxt":      weComTextCardButton,
+		}
+		return payload
+	}
+	payload["markdown"] = map[string]string{"content": buildMarkdown(stocks)}
+	return payload
+}
+
+// buildTextCardDescription textcard 只支持少量 div 标签，每只一行。
+func buildTextCardDescription(stocks []*model.Stock) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`<div class="gray">%s</div>`, time.Now().Format("2006-01-02 15:04")))
+	for _, s := range stocks {
+		if s == nil {
+			continue
+		}
+		b.WriteString(fmt.Sprintf(`<div class="normal">%s %s 涨幅 %.2f%%</div>`, s.Code, s.Name, s.ChangePct))
+	}
+	return b.String()
+}
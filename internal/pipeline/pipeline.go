@@ -0,0 +1,108 @@
+// Package pipeline 把一轮选股拆成可插拔的阶段（初选、补全指标、过滤、排序、截断、通知），按配置顺序依次执行。
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// State 在各阶段间传递的数据：行情 -> 初选候选 -> 补全指标的股票（经过滤/排序/截断逐步收敛）。
+type State struct {
+	Quotes     []model.StockQuote
+	Candidates []model.StockQuote
+	Stocks     []*model.Stock
+	Timings    []Timing // 已执行阶段的耗时，供调用方汇总打点
+}
+
+// Timing 单个阶段耗时。
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// FormatTimings 格式化为一行汇总，如 "list=120ms prefilter=1ms 总计=121ms"。
+func FormatTimings(ts []Timing) string {
+	var b strings.Builder
+	var total time.Duration
+	for _, t := range ts {
+		fmt.Fprintf(&b, "%s=%dms ", t.Name, t.Duration.Milliseconds())
+		total += t.Duration
+	}
+	fmt.Fprintf(&b, "总计=%dms", total.Milliseconds())
+	return b.String()
+}
+
+// Stage 单个处理阶段。
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, st *State) error
+}
+
+type funcStage struct {
+	name string
+	fn   func(ctx context.Context, st *State) error
+}
+
+func (f funcStage) Name() string                             { return f.name }
+func (f funcStage) Run(ctx context.Context, st *State) error { return f.fn(ctx, st) }
+
+// New 用函数构造阶段。
+func New(name string, fn func(ctx context.Context, st *State) error) Stage {
+	return funcStage{name: name, fn: fn}
+}
+
+// Pipeline 按顺序执行的阶段列表。
+type Pipeline []Stage
+
+// Build 按名称顺序从 available 中组装流水线，名称未注册时返回错误。
+func Build(order []string, available map[string]Stage) (Pipeline, error) {
+	p := make(Pipeline, 0, len(order))
+	for _, name := range order {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		st, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("pipeline: unknown stage %q", name)
+		}
+		p = append(p, st)
+	}
+	if len(p) == 0 {
+		return nil, fmt.Errorf("pipeline: no stages")
+	}
+	return p, nil
+}
+
+// Names 返回各阶段名称，用于日志。
+func (p Pipeline) Names() []string {
+	names := make([]string, len(p))
+	for i, st := range p {
+		names[i] = st.Name()
+	}
+	return names
+}
+
+// Run 依次执行各阶段；ctx 取消或某阶段出错时停止并返回错误，已产生的 State 保留。
+func (p Pipeline) Run(ctx context.Context, st *State) error {
+	for _, stage := range p {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("pipeline: before %s: %w", stage.Name(), err)
+		}
+		start := time.Now()
+		err := stage.Run(ctx, st)
+		d := time.Since(start)
+		st.Timings = append(st.Timings, Timing{Name: stage.Name(), Duration: d})
+		if err != nil {
+			return fmt.Errorf("pipeline: %s: %w", stage.Name(), err)
+		}
+		trace.Log(ctx, "pipeline: %s done %s candidates=%d stocks=%d",
+			stage.Name(), d.Round(time.Millisecond), len(st.Candidates), len(st.Stocks))
+	}
+	return nil
+}
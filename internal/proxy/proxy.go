@@ -0,0 +1,105 @@
+// Package proxy 按请求域名决定是否走 HTTP 代理，便于混合内外网环境（如推送服务走代理、行情接口直连）。
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// matchAll 出现在域名列表中时匹配所有 host
+const matchAll = "*"
+
+// 连接复用参数：行情接口集中在少数几个 host 上并发请求，默认每 host 仅保留 2 个空闲连接会频繁重建 TLS
+const (
+	maxIdleConns        = 64
+	maxIdleConnsPerHost = 16
+	idleConnTimeout     = 90 * time.Second
+	tlsHandshakeTimeout = 5 * time.Second
+)
+
+// Rules 代理规则：URL 为空时沿用标准环境变量（HTTP_PROXY/HTTPS_PROXY/NO_PROXY）；
+// Domains 非空时仅这些域名（含子域名）走代理，为空时除 NoProxy 外全部走代理；NoProxy 优先。
+type Rules struct {
+	URL     *url.URL
+	Domains []string
+	NoProxy []string
+}
+
+// Parse 由代理地址与逗号分隔的域名列表构造规则，代理地址无效时返回错误。
+func Parse(proxyURL, domains, noProxy string) (Rules, error) {
+	r := Rules{Domains: splitList(domains), NoProxy: splitList(noProxy)}
+	if s := strings.TrimSpace(proxyURL); s != "" {
+		u, err := url.Parse(s)
+		if err != nil {
+			return Rules{}, err
+		}
+		r.URL = u
+	}
+	return r, nil
+}
+
+// ProxyFunc 返回 http.Transport.Proxy 使用的函数，按请求 host 决定是否用代理。
+func (r Rules) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	if r.URL == nil {
+		return http.ProxyFromEnvironment
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if r.UseProxy(req.URL.Hostname()) {
+			return r.URL, nil
+		}
+		return nil, nil
+	}
+}
+
+// UseProxy 判断 host 是否走代理。
+func (r Rules) UseProxy(host string) bool {
+	if r.URL == nil {
+		return false
+	}
+	if matchHost(r.NoProxy, host) {
+		return false
+	}
+	return len(r.Domains) == 0 || matchHost(r.Domains, host)
+}
+
+// Transport 基于默认 Transport 克隆，替换 Proxy 并调大连接复用参数；零值 Rules 即按系统环境变量走代理，与默认 Transport 一致。
+func (r Rules) Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = r.ProxyFunc()
+	t.MaxIdleConns = maxIdleConns
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.IdleConnTimeout = idleConnTimeout
+	t.TLSHandshakeTimeout = tlsHandshakeTimeout
+	return t
+}
+
+// matchHost host 等于某域名或为其子域名（".example.com" 与 "example.com" 等价）。
+func matchHost(domains []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, d := range domains {
+		if d == matchAll {
+			return true
+		}
+		d = strings.TrimPrefix(d, ".")
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,4 @@
+package store
+
+// 纯 Go SQLite 驱动（注册名 sqlite），免 CGO，交叉编译不受影响。
+import _ "modernc.org/sqlite"
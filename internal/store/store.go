@@ -0,0 +1,134 @@
+// Package store 把每轮入选的股票连同运行时间、trace id 与指标快照写入 SQLite，便于长期回查“某天选了哪些、后来涨没涨”。
+// 驱动为纯 Go 的 modernc.org/sqlite（免 CGO），在 sqlite.go 中引入。
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// driverName modernc.org/sqlite 注册的驱动名
+const driverName = "sqlite"
+
+// dateLayout run_date 列格式（本地时区），与 K 线日期一致
+const dateLayout = "2006-01-02"
+
+const schema = `CREATE TABLE IF NOT EXISTS selections (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_at         TEXT NOT NULL,
+	run_date       TEXT NOT NULL,
+	trace_id       TEXT NOT NULL,
+	code           TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	industry       TEXT NOT NULL,
+	price          REAL NOT NULL,
+	change_pct     REAL NOT NULL,
+	turnover_rate  REAL NOT NULL,
+	volume_ratio   REAL NOT NULL,
+	market_cap     REAL NOT NULL,
+	pe             REAL NOT NULL,
+	ma5            REAL NOT NULL,
+	ma10           REAL NOT NULL,
+	ma20           REAL NOT NULL,
+	ma60           REAL NOT NULL,
+	macd_histogram REAL NOT NULL,
+	rsi14          REAL NOT NULL,
+	net_inflow     REAL NOT NULL,
+	score          REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_selections_run_date ON selections(run_date);`
+
+// columns 与 schema 对应的写入/查询列（不含自增 id），Save 与 QueryByDate 共用同一顺序。
+const columns = `run_at, run_date, trace_id, code, name, industry, price, change_pct, turnover_rate, volume_ratio,
+	market_cap, pe, ma5, ma10, ma20, ma60, macd_histogram, rsi14, net_inflow, score`
+
+// Record 一条入选记录：运行时间、trace id 与当时的指标快照（Stock 只填表中有的字段）。
+type Record struct {
+	RunAt   time.Time
+	TraceID string
+	Stock   model.Stock
+}
+
+// Store SQLite 入选记录库。
+type Store struct {
+	db *sql.DB
+}
+
+// Open 打开（不存在则创建）path 处的数据库并建表。
+func Open(path string) (*Store, error) {
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store: init schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save 在一个事务内写入本轮入选，trace id 取自 ctx。
+func (s *Store) Save(ctx context.Context, runTime time.Time, stocks []*model.Stock) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO selections (`+columns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("store: prepare: %w", err)
+	}
+	defer stmt.Close()
+	runAt, runDate, traceID := runTime.Format(time.RFC3339), runTime.Local().Format(dateLayout), trace.TraceID(ctx)
+	for _, st := range stocks {
+		if st == nil {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, runAt, runDate, traceID, st.Code, st.Name, st.Industry, st.Price, st.ChangePct,
+			st.TurnoverRate, st.VolumeRatio, st.MarketCap, st.PE, st.MA5, st.MA10, st.MA20, st.MA60,
+			st.MacdHistogram, st.RSI14, st.NetInflow, st.Score); err != nil {
+			return fmt.Errorf("store: insert %s: %w", st.Code, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: commit: %w", err)
+	}
+	return nil
+}
+
+// QueryByDate 返回某日（本地时区 YYYY-MM-DD）的全部入选记录，按运行时间、代码排序。
+func (s *Store) QueryByDate(date string) ([]Record, error) {
+	rows, err := s.db.Query(`SELECT `+columns+` FROM selections WHERE run_date = ? ORDER BY run_at, code`, date)
+	if err != nil {
+		return nil, fmt.Errorf("store: query %s: %w", date, err)
+	}
+	defer rows.Close()
+	var out []Record
+	for rows.Next() {
+		var r Record
+		var runAt, runDate string
+		st := &r.Stock
+		if err := rows.Scan(&runAt, &runDate, &r.TraceID, &st.Code, &st.Name, &st.Industry, &st.Price, &st.ChangePct,
+			&st.TurnoverRate, &st.VolumeRatio, &st.MarketCap, &st.PE, &st.MA5, &st.MA10, &st.MA20, &st.MA60,
+			&st.MacdHistogram, &st.RSI14, &st.NetInflow, &st.Score); err != nil {
+			return nil, fmt.Errorf("store: scan: %w", err)
+		}
+		if r.RunAt, err = time.Parse(time.RFC3339, runAt); err != nil {
+			return nil, fmt.Errorf("store: parse run_at %q: %w", runAt, err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// Save 写入的快照经 QueryByDate 原样读回：按运行时间、代码排序，只返回当日记录，trace id 取自 ctx。
+func TestSaveQueryByDateRoundTrip(t *testing.T) {
+	st, err := Open(filepath.Join(t.TempDir(), "selections.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	ctx := trace.WithTraceID(context.Background(), "trace-1")
+	morning := time.Date(2026, 1, 9, 10, 0, 0, 0, time.Local)
+	afternoon := morning.Add(4 * time.Hour)
+	nextDay := morning.AddDate(0, 0, 1)
+	moutai := &model.Stock{
+		Code: "600519", Name: "贵州茅台", Industry: "白酒", Price: 1500.5, ChangePct: 3.2,
+		TurnoverRate: 0.4, VolumeRatio: 1.3, MarketCap: 1.9e12, PE: 28.5,
+		MA5: 1490, MA10: 1480, MA20: 1470, MA60: 1450, MacdHistogram: 2.5, RSI14: 61.2, NetInflow: 3e8, Score: 88,
+	}
+	pingan := &model.Stock{Code: "000001", Name: "平安银行", Industry: "银行", Price: 11.2, Score: 70}
+
+	if err := st.Save(ctx, afternoon, []*model.Stock{moutai}); err != nil {
+		t.Fatalf("Save afternoon: %v", err)
+	}
+	if err := st.Save(ctx, morning, []*model.Stock{moutai, nil, pingan}); err != nil {
+		t.Fatalf("Save morning: %v", err)
+	}
+	if err := st.Save(ctx, nextDay, []*model.Stock{pingan}); err != nil {
+		t.Fatalf("Save next day: %v", err)
+	}
+	if err := st.Save(ctx, morning, nil); err != nil {
+		t.Fatalf("Save empty: %v", err)
+	}
+
+	got, err := st.QueryByDate(morning.Format(dateLayout))
+	if err != nil {
+		t.Fatalf("QueryByDate: %v", err)
+	}
+	want := []struct {
+		runAt time.Time
+		stock *model.Stock
+	}{
+		{morning, pingan},
+		{morning, moutai},
+		{afternoon, moutai},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("QueryByDate 返回 %d 条，want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if !got[i].RunAt.Equal(w.runAt) {
+			t.Errorf("[%d] RunAt = %v, want %v", i, got[i].RunAt, w.runAt)
+		}
+		if got[i].TraceID != "trace-1" {
+			t.Errorf("[%d] TraceID = %q, want trace-1", i, got[i].TraceID)
+		}
+		if !reflect.DeepEqual(got[i].Stock, *w.stock) {
+			t.Errorf("[%d] Stock = %+v, want %+v", i, got[i].Stock, *w.stock)
+		}
+	}
+
+	none, err := st.QueryByDate("2026-01-01")
+	if err != nil {
+		t.Fatalf("QueryByDate 无记录日期: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("无记录日期返回 %d 条，want 0", len(none))
+	}
+}
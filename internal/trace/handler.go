@@ -0,0 +1,199 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level 日志级别，Handler 只输出不低于自身级别的记录。
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+func (l Level) String() string {
+	if s, ok := levelNames[l]; ok {
+		return s
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// ParseLevel 解析 debug/info/warn/error（不区分大小写）。
+func ParseLevel(s string) (Level, bool) {
+	for l, name := range levelNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) || (l == LevelWarn && strings.EqualFold(s, "warning")) {
+			return l, true
+		}
+	}
+	return LevelDebug, false
+}
+
+// Format 输出格式：text 为“时间 级别 TRACE=id | msg”，json 每行一个对象。
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
+// ParseFormat 解析 text/json。
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text":
+		return FormatText, true
+	case "json":
+		return FormatJSON, true
+	}
+	return FormatText, false
+}
+
+// Record 一条日志。
+type Record struct {
+	Time    time.Time
+	Level   Level
+	TraceID string
+	Msg     string
+}
+
+// Handler 日志输出端（sink），可组合多个，每个独立过滤级别与格式。
+type Handler interface {
+	Enabled(Level) bool
+	Handle(Record)
+}
+
+const recordTimeFormat = "2006/01/02 15:04:05"
+
+func formatRecord(r Record, f Format) string {
+	if f == FormatJSON {
+		b, _ := json.Marshal(struct {
+			Time    string `json:"time"`
+			Level   string `json:"level"`
+			TraceID string `json:"trace"`
+			Msg     string `json:"msg"`
+		}{r.Time.Format(time.RFC3339), r.Level.String(), r.TraceID, r.Msg})
+		return string(b)
+	}
+	return fmt.Sprintf("%s %s TRACE=%s | %s", r.Time.Format(recordTimeFormat), r.Level, r.TraceID, r.Msg)
+}
+
+// writerHandler 把记录按格式写到 io.Writer（stdout/stderr/文件）。
+type writerHandler struct {
+	mu     sync.Mutex
+	w      io.Writer
+	min    Level
+	format Format
+}
+
+// NewWriterHandler 输出到 w，只写级别 >= min 的记录。
+func NewWriterHandler(w io.Writer, min Level, format Format) Handler {
+	return &writerHandler{w: w, min: min, format: format}
+}
+
+func (h *writerHandler) Enabled(l Level) bool { return l >= h.min }
+
+func (h *writerHandler) Handle(r Record) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintln(h.w, formatRecord(r, h.format))
+}
+
+// stdLogHandler 经标准库 log 输出（沿用 main 设置的 log flags），为默认 sink。
+type stdLogHandler struct{ min Level }
+
+func (h stdLogHandler) Enabled(l Level) bool { return l >= h.min }
+
+func (h stdLogHandler) Handle(r Record) {
+	if r.Level == LevelInfo {
+		log.Printf("TRACE=%s | %s", r.TraceID, r.Msg)
+		return
+	}
+	log.Printf("%s TRACE=%s | %s", r.Level, r.TraceID, r.Msg)
+}
+
+// handlers 当前生效的 sink；默认仅标准库 log、全级别输出。
+var (
+	handlersMu sync.RWMutex
+	handlers   = []Handler{stdLogHandler{min: LevelDebug}}
+)
+
+// SetHandlers 替换全部 sink；传空则恢复默认。
+func SetHandlers(hs ...Handler) {
+	if len(hs) == 0 {
+		hs = []Handler{stdLogHandler{min: LevelDebug}}
+	}
+	handlersMu.Lock()
+	handlers = hs
+	handlersMu.Unlock()
+}
+
+// AddHandler 在现有 sink 之后追加一个。
+func AddHandler(h Handler) {
+	handlersMu.Lock()
+	handlers = append(append([]Handler(nil), handlers...), h)
+	handlersMu.Unlock()
+}
+
+func dispatch(r Record) {
+	handlersMu.RLock()
+	hs := handlers
+	handlersMu.RUnlock()
+	for _, h := range hs {
+		if h.Enabled(r.Level) {
+			h.Handle(r)
+		}
+	}
+}
+
+// ParseSinks 解析 sink 配置：逗号分隔，每项为 target[:level[:format]]，target 为 stdout、stderr 或文件路径；
+// 如 "stderr:warn,/var/log/stockMaxWin.log:debug:json"。文件以追加方式打开。未写级别为 debug、格式为 text。
+func ParseSinks(spec string) ([]Handler, error) {
+	var hs []Handler
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		format := FormatText
+		level := LevelDebug
+		if f, ok := ParseFormat(parts[len(parts)-1]); ok && len(parts) > 1 {
+			format = f
+			parts = parts[:len(parts)-1]
+		}
+		if l, ok := ParseLevel(parts[len(parts)-1]); ok && len(parts) > 1 {
+			level = l
+			parts = parts[:len(parts)-1]
+		}
+		target := strings.Join(parts, ":")
+		switch target {
+		case "stdout":
+			hs = append(hs, NewWriterHandler(os.Stdout, level, format))
+		case "stderr":
+			hs = append(hs, NewWriterHandler(os.Stderr, level, format))
+		default:
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, traceFilePerm)
+			if err != nil {
+				return nil, fmt.Errorf("trace: open sink %s: %w", target, err)
+			}
+			hs = append(hs, NewWriterHandler(f, level, format))
+		}
+	}
+	return hs, nil
+}
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotateDateFormat 按天切割后旧文件的日期后缀，如 stockMaxWin.log.2026-01-05
+const rotateDateFormat = "2006-01-02"
+
+// dailyFileHandler 写入固定路径的日志文件，跨天后首条日志前把旧文件改名为 path.日期 再新建，供 STOCKMAXWIN_LOG_FILE 使用。
+type dailyFileHandler struct {
+	mu     sync.Mutex
+	path   string
+	min    Level
+	format Format
+	f      *os.File
+	day    string // 当前文件对应的日期
+}
+
+// NewDailyFileHandler 以追加方式打开 path，按天切割；已存在的文件以其修改日期作为所属日期。
+func NewDailyFileHandler(path string, min Level, format Format) (Handler, error) {
+	h := &dailyFileHandler{path: path, min: min, format: format}
+	if err := h.open(time.Now()); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *dailyFileHandler) open(now time.Time) error {
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, traceFilePerm)
+	if err != nil {
+		return fmt.Errorf("trace: open log file %s: %w", h.path, err)
+	}
+	h.f, h.day = f, now.Format(rotateDateFormat)
+	if st, err := f.Stat(); err == nil && st.Size() > 0 {
+		h.day = st.ModTime().Format(rotateDateFormat)
+	}
+	return nil
+}
+
+func (h *dailyFileHandler) Enabled(l Level) bool { return l >= h.min }
+
+func (h *dailyFileHandler) Handle(r Record) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if day := r.Time.Format(rotateDateFormat); day != h.day {
+		h.rotate(r.Time)
+	}
+	if h.f == nil {
+		return
+	}
+	fmt.Fprintln(h.f, formatRecord(r, h.format))
+}
+
+// rotate 关闭当前文件并改名为 path.日期，再打开新文件；失败时只打到标准 logger，尽量继续写原路径。
+func (h *dailyFileHandler) rotate(now time.Time) {
+	if h.f != nil {
+		_ = h.f.Close()
+		h.f = nil
+	}
+	if err := os.Rename(h.path, h.path+"."+h.day); err != nil && !os.IsNotExist(err) {
+		log.Printf("trace: rotate %s err=%v", h.path, err)
+	}
+	if err := h.open(now); err != nil {
+		log.Printf("%v", err)
+		return
+	}
+	h.day = now.Format(rotateDateFormat)
+}
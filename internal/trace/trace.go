@@ -7,7 +7,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 type ctxKey int
@@ -16,6 +20,7 @@ const (
 	traceIDKey     ctxKey = 0
 	traceIDFallback       = "0"
 	traceIDBytes          = 4
+	subTraceSep           = "."
 )
 
 func WithTraceID(ctx context.Context, id string) context.Context {
@@ -29,6 +34,21 @@ func TraceID(ctx context.Context) string {
 	return ""
 }
 
+// WithSubTraceID 在 ctx 的 trace 下派生子 trace（父 id + "." + sub），区分同一轮中并发的 goroutine；
+// 按 trace 分文件时子 trace 仍写入父 trace 的文件。ctx 无 trace 时直接用 sub。
+func WithSubTraceID(ctx context.Context, sub string) context.Context {
+	if parent := TraceID(ctx); parent != "" {
+		sub = parent + subTraceSep + sub
+	}
+	return WithTraceID(ctx, sub)
+}
+
+// rootTraceID 去掉子 trace 后缀，得到本轮的 trace id。
+func rootTraceID(id string) string {
+	root, _, _ := strings.Cut(id, subTraceSep)
+	return root
+}
+
 func NewTraceID() string {
 	b := make([]byte, traceIDBytes)
 	if _, err := rand.Read(b); err != nil {
@@ -41,14 +61,94 @@ var logMu sync.Mutex
 
 const traceIDEmpty = "-"
 
-// Log 打日志，每行开头固定为 TRACE=id，便于一眼看到 trace 并 grep
+// 按 trace 分文件：文件名 日期_traceID.log，每行带时间
+const (
+	traceFileDateFormat = "2006-01-02"
+	traceFileTimeFormat = "2006/01/02 15:04:05"
+	traceFilePerm       = 0o644
+	traceDirPerm        = 0o755
+)
+
+// traceDir 非空时每条日志额外追加到 traceDir 下该 trace 的独立文件。
+var traceDir string
+
+// SetTraceDir 开启按 trace 分文件写日志（每轮一个 trace 即每轮一个文件），dir 为空则关闭。
+func SetTraceDir(dir string) error {
+	logMu.Lock()
+	defer logMu.Unlock()
+	if dir == "" {
+		traceDir = ""
+		return nil
+	}
+	if err := os.MkdirAll(dir, traceDirPerm); err != nil {
+		return err
+	}
+	traceDir = dir
+	return nil
+}
+
+// writeTraceFile 追加一行到该 trace 的文件；需持有 logMu。写失败只打到标准 logger，不影响主日志。
+func writeTraceFile(id, msg string) {
+	if traceDir == "" {
+		return
+	}
+	now := time.Now()
+	name := filepath.Join(traceDir, now.Format(traceFileDateFormat)+"_"+rootTraceID(id)+".log")
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, traceFilePerm)
+	if err != nil {
+		log.Printf("trace: open %s err=%v", name, err)
+		return
+	}
+	fmt.Fprintf(f, "%s TRACE=%s | %s\n", now.Format(traceFileTimeFormat), id, msg)
+	_ = f.Close()
+}
+
+// Log 打 INFO 日志，每行开头固定为 TRACE=id，便于一眼看到 trace 并 grep
 func Log(ctx context.Context, format string, args ...interface{}) {
+	logAt(ctx, LevelInfo, format, args...)
+}
+
+// Debug 打 DEBUG 日志（请求/响应明细等高频信息）。
+func Debug(ctx context.Context, format string, args ...interface{}) {
+	logAt(ctx, LevelDebug, format, args...)
+}
+
+// Warn 打 WARN 日志（数据异常、可自动恢复的失败）。
+func Warn(ctx context.Context, format string, args ...interface{}) {
+	logAt(ctx, LevelWarn, format, args...)
+}
+
+// Error 打 ERROR 日志。
+func Error(ctx context.Context, format string, args ...interface{}) {
+	logAt(ctx, LevelError, format, args...)
+}
+
+// Logf 按指定级别打日志，低于全局阈值（SetLevel）的不输出到各 sink。
+func Logf(ctx context.Context, level Level, format string, args ...interface{}) {
+	logAt(ctx, level, format, args...)
+}
+
+// minLevel 全局输出阈值，默认 DEBUG 即全部输出；各 sink 另有自身级别。
+var minLevel = LevelDebug
+
+// SetLevel 设置全局输出阈值（STOCKMAXWIN_LOG_LEVEL），生产环境可调到 INFO/WARN 降噪。
+func SetLevel(l Level) {
+	logMu.Lock()
+	minLevel = l
+	logMu.Unlock()
+}
+
+// logAt 分发到各 sink；按 trace 分文件始终全量写入，不受全局阈值影响。
+func logAt(ctx context.Context, level Level, format string, args ...interface{}) {
 	id := TraceID(ctx)
 	if id == "" {
 		id = traceIDEmpty
 	}
 	logMu.Lock()
 	msg := fmt.Sprintf(format, args...)
-	log.Printf("TRACE=%s | %s", id, msg)
+	if level >= minLevel {
+		dispatch(Record{Time: time.Now(), Level: level, TraceID: id, Msg: msg})
+	}
+	writeTraceFile(id, msg)
 	logMu.Unlock()
 }
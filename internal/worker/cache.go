@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"sync"
+
+	"stockMaxWin/internal/model"
+)
+
+// StockCache 同一轮内按代码缓存 fetchAndMerge 的结果（拉 K 线 + 算指标后的快照），多个策略或多次 Pool.Run 共享，
+// 只有过滤逻辑不同。同一代码并发请求时只有一个 goroutine 真正计算，其余等待其结果。
+// 每次取出的是快照的副本，各 Pool 打分、补概况只改自己的副本，互不覆盖。
+// 失败（返回 nil）同样缓存，失败原因只记录在首次计算的 Pool 上。每轮新建，不跨轮复用（行情与 K 线会变）。
+type StockCache struct {
+	mu      sync.Mutex
+	entries map[string]*stockCacheEntry
+}
+
+type stockCacheEntry struct {
+	once  sync.Once
+	stock *model.Stock
+}
+
+func NewStockCache() *StockCache {
+	return &StockCache{entries: make(map[string]*stockCacheEntry)}
+}
+
+// get 返回 code 缓存快照的副本，首次访问时调用 compute 计算；c 为 nil 时直接计算。
+// 副本与快照共享切片字段（如 Concepts），调用方只能整体替换，不能原地修改其元素。
+func (c *StockCache) get(code string, compute func() *model.Stock) *model.Stock {
+	if c == nil {
+		return compute()
+	}
+	c.mu.Lock()
+	e, ok := c.entries[code]
+	if !ok {
+		e = &stockCacheEntry{}
+		c.entries[code] = e
+	}
+	c.mu.Unlock()
+	e.once.Do(func() { e.stock = compute() })
+	if e.stock == nil {
+		return nil
+	}
+	s := *e.stock
+	return &s
+}
+
+// Len 已缓存的代码数。
+func (c *StockCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
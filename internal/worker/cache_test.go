@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"stockMaxWin/internal/api"
+	"stockMaxWin/internal/model"
+)
+
+// klineServer 按 fields2=f51..f56 顺序返回 n 根收盘价递增的日 K，并统计请求次数。
+func klineServer(t *testing.T, n int, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		klines := make([]string, n)
+		for i := range klines {
+			c := 10 + float64(i)*0.1
+			klines[i] = fmt.Sprintf(`"2024-01-%02d,%.2f,%.2f,%.2f,%.2f,1000"`, i+1, c, c, c+0.1, c-0.1)
+		}
+		fmt.Fprintf(w, `{"rc":0,"data":{"code":%q,"klines":[%s]}}`, r.URL.Query().Get("secid"), strings.Join(klines, ","))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// 两个 Pool 共享同一个 StockCache 并发运行：每只股票只拉一次 K 线，各 Pool 的打分写在各自副本上互不覆盖。
+// 以 go test -race 运行可检查共享快照上没有并发写。
+func TestStockCacheSharedByPools(t *testing.T) {
+	const klineCount = 30
+	var hits atomic.Int32
+	srv := klineServer(t, klineCount, &hits)
+	client := api.NewClientWithOptions(api.ClientOptions{Limits: api.Limits{RPS: 1000, Burst: 100, MaxConcurrent: 8}})
+	client.KLineURL = srv.URL
+
+	codes := []string{"600000", "600519", "000001", "000002"}
+	cache := NewStockCache()
+	run := func(score float64) []*model.Stock {
+		cfg := DefaultConfig()
+		cfg.Concurrency = 2
+		cfg.KlineCount = klineCount
+		cfg.Filter = func(*model.Stock) bool { return true }
+		cfg.Scorer = func(*model.Stock) float64 { return score }
+		cfg.Cache = cache
+		jobs := make(chan model.StockQuote, len(codes))
+		results := make(chan *model.Stock, len(codes))
+		for _, code := range codes {
+			jobs <- model.StockQuote{Code: code, Name: code, Price: 12.9}
+		}
+		close(jobs)
+		NewPool(cfg, client, jobs, results).Run(context.Background())
+		var out []*model.Stock
+		for s := range results {
+			out = append(out, s)
+		}
+		return out
+	}
+
+	var wg sync.WaitGroup
+	got := make([][]*model.Stock, 2)
+	for i, score := range []float64{1, 2} {
+		wg.Add(1)
+		go func(i int, score float64) {
+			defer wg.Done()
+			got[i] = run(score)
+		}(i, score)
+	}
+	wg.Wait()
+
+	if n := int(hits.Load()); n != len(codes) {
+		t.Errorf("K 线请求 %d 次，want %d（每只一次）", n, len(codes))
+	}
+	if n := cache.Len(); n != len(codes) {
+		t.Errorf("cache.Len() = %d, want %d", n, len(codes))
+	}
+	seen := make(map[*model.Stock]bool)
+	for i, want := range []float64{1, 2} {
+		if len(got[i]) != len(codes) {
+			t.Fatalf("Pool %d 返回 %d 只，want %d", i+1, len(got[i]), len(codes))
+		}
+		for _, s := range got[i] {
+			if s.Score != want {
+				t.Errorf("Pool %d %s Score = %v, want %v", i+1, s.Code, s.Score, want)
+			}
+			if seen[s] {
+				t.Errorf("Pool %d %s 与另一 Pool 共用同一个 *model.Stock", i+1, s.Code)
+			}
+			seen[s] = true
+		}
+	}
+}
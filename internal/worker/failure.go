@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FailureReason 单只股票未能补全指标的原因。
+type FailureReason string
+
+// 失败原因
+const (
+	ReasonNoData       FailureReason = "no_data"             // 无 K 线数据（停牌/新股/代码无效）
+	ReasonFetch        FailureReason = "fetch_error"         // 网络或解析失败，可重试
+	ReasonInsufficient FailureReason = "insufficient_klines" // K 线根数不足以计算 MA20
+	ReasonPanic        FailureReason = "panic"               // 脏数据导致计算 panic
+)
+
+// Failure 被丢弃的股票及原因，Err 为底层错误（K 线不足时为 nil）。
+type Failure struct {
+	Code   string
+	Name   string
+	Reason FailureReason
+	Err    error
+}
+
+// Retryable 是否值得重试：仅网络/解析失败，无数据与 K 线不足是确定结果。
+func (f Failure) Retryable() bool {
+	return f.Reason == ReasonFetch
+}
+
+// CountFailures 按原因计数。
+func CountFailures(fs []Failure) map[FailureReason]int {
+	m := make(map[FailureReason]int)
+	for _, f := range fs {
+		m[f.Reason]++
+	}
+	return m
+}
+
+// FormatFailureCounts 格式化为一行，如 "fetch_error=2 no_data=1"，原因按名称排序；无失败为 "0"。
+func FormatFailureCounts(fs []Failure) string {
+	counts := CountFailures(fs)
+	if len(counts) == 0 {
+		return "0"
+	}
+	reasons := make([]string, 0, len(counts))
+	for r := range counts {
+		reasons = append(reasons, string(r))
+	}
+	sort.Strings(reasons)
+	parts := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		parts = append(parts, fmt.Sprintf("%s=%d", r, counts[FailureReason(r)]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,59 @@
+package worker
+
+// 指标名：用于按启用的指标推导 K 线请求数量
+const (
+	IndicatorMA5      = "ma5"
+	IndicatorMA10     = "ma10"
+	IndicatorMA20     = "ma20"
+	IndicatorMA60     = "ma60"
+	IndicatorMA60Up   = "ma60_up"
+	IndicatorMACross  = "ma20_cross_ma60"
+	IndicatorMACD     = "macd"
+	IndicatorDrawdown = "drawdown"
+	IndicatorVolMA5   = "vol_ma5"
+	IndicatorRSI14    = "rsi14"
+	IndicatorBoll     = "boll"
+	IndicatorATR14    = "atr14"
+	IndicatorNewHigh  = "new_high"
+)
+
+// macdWarmup MACD 至少需要 slow+signal 根，EMA 还需额外预热才收敛，按 80 根计
+const macdWarmup = 80
+
+// indicatorKlines 各指标所需的最少 K 线根数
+var indicatorKlines = map[string]int{
+	IndicatorMA5:      maPeriod5,
+	IndicatorMA10:     maPeriod10,
+	IndicatorMA20:     maPeriod20,
+	IndicatorMA60:     maPeriod60,
+	IndicatorMA60Up:   maPeriod60 + ma60TrendLookback,
+	IndicatorMACross:  maPeriod60 + 1,
+	IndicatorMACD:     macdWarmup,
+	IndicatorDrawdown: drawdownLookback,
+	IndicatorVolMA5:   maPeriod5 + 1,
+	IndicatorRSI14:    rsiWarmup,
+	IndicatorBoll:     bollPeriod,
+	IndicatorATR14:    rsiWarmup,
+	IndicatorNewHigh:  newHighLookback,
+}
+
+// AllIndicators 返回全部内置指标名（内置趋势动能策略按全部指标拉 K 线）。
+func AllIndicators() []string {
+	names := make([]string, 0, len(indicatorKlines))
+	for name := range indicatorKlines {
+		names = append(names, name)
+	}
+	return names
+}
+
+// KlineCountFor 返回计算给定指标所需的 K 线根数（取各指标所需最大值），
+// 至少 minKlinesForMA20 根；未知指标名忽略。
+func KlineCountFor(indicators ...string) int {
+	n := minKlinesForMA20
+	for _, name := range indicators {
+		if k := indicatorKlines[name]; k > n {
+			n = k
+		}
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+
+	"stockMaxWin/internal/api"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// fillProfile 主营或行业为空时用公司概况补齐；拉取失败只记日志，保持空（展示为 -）。
+func fillProfile(ctx context.Context, apiClient *api.Client, s *model.Stock) {
+	if s == nil || (s.MainBusiness != "" && s.Industry != "") {
+		return
+	}
+	p, err := apiClient.GetStockProfile(ctx, s.Code)
+	if err != nil {
+		trace.Log(ctx, "worker: GetStockProfile code=%s err=%v，主营留空", s.Code, err)
+		return
+	}
+	if s.MainBusiness == "" {
+		s.MainBusiness = p.MainBusiness
+	}
+	if s.Industry == "" {
+		s.Industry = p.Industry
+	}
+}
+
+// FillProfiles 为最终入选的股票补充主营业务与行业。只对少数入选票请求，且 Client 按代码缓存概况，
+// 调度模式下同一只票重复入选不会重复请求。
+func FillProfiles(ctx context.Context, apiClient *api.Client, stocks []*model.Stock) {
+	for _, s := range stocks {
+		if ctx.Err() != nil {
+			return
+		}
+		fillProfile(ctx, apiClient, s)
+	}
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"math"
+
+	"stockMaxWin/internal/model"
+)
+
+// ScoreFunc 对通过 Filter 的股票打分，分数越高越靠前；结果写入 Stock.Score。
+type ScoreFunc func(*model.Stock) float64
+
+// 多因子评分权重：涨幅、量比、MACD 红柱增幅、主力净流入
+const (
+	scoreWeightChangePct   = 0.3
+	scoreWeightVolumeRatio = 0.25
+	scoreWeightMacdGrowth  = 0.2
+	scoreWeightNetInflow   = 0.25
+)
+
+// 各因子归一化到 [0,1] 的区间：涨幅 0~10%、量比 1~5、红柱较昨日增长 0~100%、主力净流入 -1 亿~+1 亿
+const (
+	scoreChangePctMax   = 10
+	scoreVolumeRatioMin = 1
+	scoreVolumeRatioMax = 5
+	scoreNetInflowRange = 1e8
+)
+
+// MultiFactorScore 综合涨幅、量比、MACD 红柱增幅与主力净流入的加权分（0~100）。
+// 各因子先截断到固定区间再线性归一化，避免单一因子（如巨额净流入）主导排序。
+func MultiFactorScore(s *model.Stock) float64 {
+	if s == nil {
+		return 0
+	}
+	change := clamp01(s.ChangePct / scoreChangePctMax)
+	vr := clamp01((s.VolumeRatio - scoreVolumeRatioMin) / (scoreVolumeRatioMax - scoreVolumeRatioMin))
+	macd := 0.0
+	if s.MacdHistogram > 0 {
+		if s.MacdHistogramPrev > 0 {
+			macd = clamp01((s.MacdHistogram - s.MacdHistogramPrev) / s.MacdHistogramPrev)
+		} else {
+			macd = 1 // 由绿翻红
+		}
+	}
+	net := s.NetInflow
+	if net == 0 {
+		net = s.MainForceInflow - s.MainForceOutflow
+	}
+	inflow := clamp01((net/scoreNetInflowRange + 1) / 2)
+	return 100 * (scoreWeightChangePct*change +
+		scoreWeightVolumeRatio*vr +
+		scoreWeightMacdGrowth*macd +
+		scoreWeightNetInflow*inflow)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,33 @@
+package worker
+
+import "sync/atomic"
+
+// Stats 一次 Run 的处理统计：Processed = Fetched + KlineFailed，Fetched = Filtered + Selected。
+// 用于判断漏选是策略过严还是拉 K 线失败（如被限流）导致。
+type Stats struct {
+	Processed   int // 从 jobs 取出并处理的股票数
+	Fetched     int // 拉到 K 线并算出指标的股票数
+	KlineFailed int // 无 K 线、拉取失败、K 线不足或 panic 被丢弃的股票数
+	Filtered    int // 被 Filter 淘汰的股票数
+	Selected    int // 通过 Filter 的股票数
+}
+
+// poolStats Stats 的原子计数器，worker goroutine 并发累加。
+type poolStats struct {
+	processed, fetched, klineFailed, filtered, selected atomic.Int64
+}
+
+func (s *poolStats) snapshot() Stats {
+	return Stats{
+		Processed:   int(s.processed.Load()),
+		Fetched:     int(s.fetched.Load()),
+		KlineFailed: int(s.klineFailed.Load()),
+		Filtered:    int(s.filtered.Load()),
+		Selected:    int(s.selected.Load()),
+	}
+}
+
+// Stats 返回本次 Run 的处理统计，应在 Run 返回（results 已关闭）后调用。
+func (p *Pool) Stats() Stats {
+	return p.stats.snapshot()
+}
@@ -3,7 +3,13 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strconv"
 	"sync"
+	"time"
 
 	"stockMaxWin/internal/api"
 	"stockMaxWin/internal/model"
@@ -12,10 +18,20 @@ import (
 
 // 并发与 K 线数量
 const (
-	defaultConcurrency    = 10
-	minKlinesForMA20      = 20
-	klineCountForStrategy = 80
-	ma60TrendLookback     = 5
+	defaultConcurrency = 10
+	minKlinesForMA20   = 20
+	ma60TrendLookback  = 5
+	drawdownLookback   = 60
+	newHighLookback    = 60
+)
+
+// defaultPriceDeviationWarnPct 现价与最新 K 线收盘价偏差超过该百分比时告警（盘中最新 K 线即当日，正常应一致）
+const defaultPriceDeviationWarnPct = 2.0
+
+// K 线网络失败时的补充重试（api 内部已对单次请求重试，这里应对短时断网）
+const (
+	klineFetchRetries = 1
+	klineRetryDelay   = 2 * time.Second
 )
 
 // 均线周期（日）
@@ -36,6 +52,21 @@ const (
 // MACD 红柱倍数（柱 = 2*(DIF-DEA)）
 const macdHistogramMultiplier = 2
 
+// 布林带：20 日中轨（MA20），上下轨为中轨 ± 2 倍收盘价样本标准差
+const (
+	bollPeriod = maPeriod20
+	bollWidth  = 2
+)
+
+// RSI 周期（日）与 Wilder 平滑预热根数（平滑需足够历史才与行情软件一致）
+const (
+	rsiPeriod14 = 14
+	rsiWarmup   = 60
+)
+
+// ATR 周期（日），Wilder 平滑，预热与 RSI 相同
+const atrPeriod14 = 14
+
 func MA5(klines []model.KLine) float64  { return maN(klines, maPeriod5) }
 func MA10(klines []model.KLine) float64 { return maN(klines, maPeriod10) }
 func MA20(klines []model.KLine) float64 { return maN(klines, maPeriod20) }
@@ -66,21 +97,132 @@ func maNAt(klines []model.KLine, n, offset int) float64 {
 	return sum / float64(n)
 }
 
+// ma20CrossUpMA60 比较今日与昨日的 MA20/MA60：昨日 MA20<=MA60 且今日 MA20>MA60 为刚上穿；数据不足 61 根为 false。
+func ma20CrossUpMA60(klines []model.KLine) bool {
+	if len(klines) < maPeriod60+1 {
+		return false
+	}
+	ma20Now, ma60Now := maNAt(klines, maPeriod20, 0), maNAt(klines, maPeriod60, 0)
+	ma20Prev, ma60Prev := maNAt(klines, maPeriod20, 1), maNAt(klines, maPeriod60, 1)
+	return ma20Prev <= ma60Prev && ma20Now > ma60Now
+}
+
+// volumeKlines 返回量能类指标使用的 K 线：includeSuspended 为 false 时剔除 volume<=0 的停牌日。
+func volumeKlines(klines []model.KLine, includeSuspended bool) []model.KLine {
+	if includeSuspended {
+		return klines
+	}
+	out := make([]model.KLine, 0, len(klines))
+	for i := range klines {
+		if klines[i].Volume > 0 {
+			out = append(out, klines[i])
+		}
+	}
+	return out
+}
+
+// volumeMA 最近 n 根 K 线的平均成交量（手），不足 n 根返回 0。
+func volumeMA(klines []model.KLine, n int) float64 {
+	if n <= 0 || len(klines) < n {
+		return 0
+	}
+	var sum float64
+	for _, k := range klines[len(klines)-n:] {
+		sum += float64(k.Volume)
+	}
+	return sum / float64(n)
+}
+
+// drawdownFromHigh 近 n 日最高收盘价（含现价）与当前回调幅度(%)；K 线不足 n 根时返回 0, 0。
+func drawdownFromHigh(klines []model.KLine, price float64, n int) (high, drawdownPct float64) {
+	if len(klines) < n || price <= 0 {
+		return 0, 0
+	}
+	high = price
+	for _, k := range klines[len(klines)-n:] {
+		if k.Close > high {
+			high = k.Close
+		}
+	}
+	return high, (high - price) / high * 100
+}
+
+// upStreak 截至最后一根 K 线连续上涨（收盘价高于前一日收盘）的天数。
+func upStreak(klines []model.KLine) int {
+	n := 0
+	for i := len(klines) - 1; i > 0; i-- {
+		if klines[i-1].Close <= 0 || klines[i].Close <= klines[i-1].Close {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// newHighDays 最后一根收盘价是近多少根 K 线（含自身）的收盘新高：从倒数第二根往前数，遇到收盘价不低于它的为止；无 K 线为 0。
+// 结果受 K 线根数限制，数据不足时自然小于要求的天数。
+func newHighDays(klines []model.KLine) int {
+	if len(klines) == 0 {
+		return 0
+	}
+	last := klines[len(klines)-1].Close
+	n := 1
+	for i := len(klines) - 2; i >= 0 && klines[i].Close < last; i-- {
+		n++
+	}
+	return n
+}
+
+// priceDeviation 现价相对最新 K 线收盘价的偏差(%)；现价或收盘价无效（停牌等）时为 0。
+func priceDeviation(price float64, klines []model.KLine) float64 {
+	if price <= 0 || len(klines) == 0 {
+		return 0
+	}
+	last := klines[len(klines)-1].Close
+	if last <= 0 {
+		return 0
+	}
+	return (price - last) / last * 100
+}
+
 // Filter 对合并后的 Stock 做是否入选判断。
 type Filter func(*model.Stock) bool
 
 func DefaultFilter(s *model.Stock) bool {
-	return s != nil && s.Price > s.MA20
+	return s != nil && s.MA20Valid && s.Price > s.MA20
 }
 
 // Config 控制并发数与筛选逻辑。
 type Config struct {
 	Concurrency int
 	Filter      Filter
+	// IncludeSuspendedVolume 为 true 时停牌日（volume<=0）也参与量能类指标计算；
+	// 默认 false 剔除停牌日，避免 0 成交量拉低均量。价格类指标始终使用完整序列。
+	IncludeSuspendedVolume bool
+	// PriceDeviationWarnPct 现价与最新 K 线收盘价偏差(%)告警阈值，<=0 时用默认值。
+	PriceDeviationWarnPct float64
+	// Scorer 非 nil 时对通过 Filter 的股票打分写入 Stock.Score；nil 不打分（调用方按涨幅等排序）。
+	Scorer ScoreFunc
+	// FundFlowDays >0 时额外拉取近 N 日资金流，计算主力净流入之和与连续净流入天数；0 不拉取。
+	FundFlowDays int
+	// MoneyFlow 为 true 时对候选调用资金流专用接口（api.GetMoneyFlow）覆盖列表的主力资金字段，拿不到时保留列表数据。
+	MoneyFlow bool
+	// KlineCount 每只股票请求的 K 线根数，由启用的指标推导（见 KlineCountFor）；<=0 时按全部指标推导。
+	KlineCount int
+	// FillProfile 为 true 时对通过 Filter 的股票拉公司概况补充主营与行业；Filter 放行全部时应关闭，改为对最终入选调用 FillProfiles。
+	FillProfile bool
+	// Cache 非 nil 时同一轮内按代码复用 fetchAndMerge 结果，避免多策略重复请求与计算，各 Pool 拿到各自的副本；nil 不缓存。
+	// 共享同一 Cache 的 Pool 应使用相同的 KlineCount、FundFlowDays 与 MoneyFlow，否则快照按先算的 Pool 配置。
+	Cache *StockCache
 }
 
 func DefaultConfig() Config {
-	return Config{Concurrency: defaultConcurrency, Filter: DefaultFilter}
+	return Config{
+		Concurrency:           defaultConcurrency,
+		Filter:                DefaultFilter,
+		KlineCount:            KlineCountFor(AllIndicators()...),
+		PriceDeviationWarnPct: defaultPriceDeviationWarnPct,
+	}
 }
 
 // macdResult 存放 MACD 当日/昨日红柱及是否刚金叉。
@@ -156,6 +298,91 @@ func ema(data []float64, period int) []float64 {
 	return out
 }
 
+// computeRSI 用 Wilder 平滑法计算最后一根 K 的 RSI：首个平均涨跌幅为前 period 日简单平均，
+// 之后 avg = (prev*(period-1) + cur) / period。K 线不足 period+1 根时返回 0；全程无下跌为 100，
+// 收盘价全程持平（平均涨幅与跌幅均为 0）时按多空均衡返回 50。
+func computeRSI(klines []model.KLine, period int) float64 {
+	if period <= 0 || len(klines) < period+1 {
+		return 0
+	}
+	var avgGain, avgLoss float64
+	for i := 1; i < len(klines); i++ {
+		diff := klines[i].Close - klines[i-1].Close
+		gain, loss := math.Max(diff, 0), math.Max(-diff, 0)
+		if i <= period {
+			avgGain += gain / float64(period)
+			avgLoss += loss / float64(period)
+			continue
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// trueRange 经典真实波幅：max(最高-最低, |最高-前收|, |最低-前收|)。
+func trueRange(k, prev model.KLine) float64 {
+	return math.Max(k.High-k.Low, math.Max(math.Abs(k.High-prev.Close), math.Abs(k.Low-prev.Close)))
+}
+
+// computeATR 用 Wilder 平滑法计算最后一根 K 的 ATR：首个 ATR 为前 period 个 TR 的简单平均，
+// 之后 atr = (prev*(period-1) + tr) / period。K 线不足 period+1 根（首根没有前收）时返回 0。
+func computeATR(klines []model.KLine, period int) float64 {
+	if period <= 0 || len(klines) < period+1 {
+		return 0
+	}
+	var atr float64
+	for i := 1; i < len(klines); i++ {
+		tr := trueRange(klines[i], klines[i-1])
+		if i <= period {
+			atr += tr / float64(period)
+			continue
+		}
+		atr = (atr*float64(period-1) + tr) / float64(period)
+	}
+	return atr
+}
+
+// atrPct ATR 占现价的百分比，ATR 或现价缺失时为 0。
+func atrPct(atr, price float64) float64 {
+	if atr <= 0 || price <= 0 {
+		return 0
+	}
+	return atr / price * 100
+}
+
+// bollResult 布林带上中下轨，K 线不足时均为 0。
+type bollResult struct {
+	upper, mid, lower float64
+}
+
+// computeBoll 取最近 period 根收盘价：中轨为均值，上下轨为中轨 ± width 倍样本标准差（除以 n-1）。
+func computeBoll(klines []model.KLine, period int, width float64) bollResult {
+	n := len(klines)
+	if period < 2 || n < period {
+		return bollResult{}
+	}
+	window := klines[n-period:]
+	var sum float64
+	for _, k := range window {
+		sum += k.Close
+	}
+	mid := sum / float64(period)
+	var sq float64
+	for _, k := range window {
+		d := k.Close - mid
+		sq += d * d
+	}
+	sd := math.Sqrt(sq / float64(period-1))
+	return bollResult{upper: mid + width*sd, mid: mid, lower: mid - width*sd}
+}
+
 // Pool 从 jobs 取行情，拉 K 线合并为 Stock，经 Filter 通过后写入 results。
 type Pool struct {
 	cfg    Config
@@ -163,6 +390,10 @@ type Pool struct {
 	jobs   <-chan model.StockQuote
 	out    chan<- *model.Stock
 	filter Filter
+
+	failMu   sync.Mutex
+	failures []Failure
+	stats    poolStats
 }
 
 func NewPool(cfg Config, apiClient *api.Client, jobs <-chan model.StockQuote, results chan<- *model.Stock) *Pool {
@@ -178,6 +409,12 @@ func NewPool(cfg Config, apiClient *api.Client, jobs <-chan model.StockQuote, re
 	if cfg.Filter == nil {
 		cfg.Filter = DefaultFilter
 	}
+	if cfg.PriceDeviationWarnPct <= 0 {
+		cfg.PriceDeviationWarnPct = defaultPriceDeviationWarnPct
+	}
+	if cfg.KlineCount <= 0 {
+		cfg.KlineCount = KlineCountFor(AllIndicators()...)
+	}
 	return &Pool{
 		cfg:    cfg,
 		api:    apiClient,
@@ -188,7 +425,7 @@ func NewPool(cfg Config, apiClient *api.Client, jobs <-chan model.StockQuote, re
 }
 
 func (p *Pool) Run(ctx context.Context) {
-	trace.Log(ctx, "worker: Pool.Run start concurrency=%d", p.cfg.Concurrency)
+	trace.Log(ctx, "worker: Pool.Run start concurrency=%d klines=%d", p.cfg.Concurrency, p.cfg.KlineCount)
 	var wg sync.WaitGroup
 	for i := 0; i < p.cfg.Concurrency; i++ {
 		wg.Add(1)
@@ -199,10 +436,30 @@ func (p *Pool) Run(ctx context.Context) {
 	}
 	wg.Wait()
 	close(p.out)
-	trace.Log(ctx, "worker: Pool.Run done")
+	st := p.Stats()
+	trace.Log(ctx, "worker: Pool.Run done processed=%d fetched=%d kline_failed=%d filtered=%d selected=%d failures=%s",
+		st.Processed, st.Fetched, st.KlineFailed, st.Filtered, st.Selected, FormatFailureCounts(p.Failures()))
+}
+
+// Failures 返回本次 Run 中因无数据、拉取失败、K 线不足或 panic 被丢弃的股票（策略未通过不算失败）。
+// 应在 Run 返回（results 已关闭）后调用。
+func (p *Pool) Failures() []Failure {
+	p.failMu.Lock()
+	defer p.failMu.Unlock()
+	return append([]Failure(nil), p.failures...)
+}
+
+func (p *Pool) recordFailure(q *model.StockQuote, reason FailureReason, err error) {
+	p.failMu.Lock()
+	p.failures = append(p.failures, Failure{Code: q.Code, Name: q.Name, Reason: reason, Err: err})
+	p.failMu.Unlock()
 }
 
+// runWorker 消费任务直到 jobs 关闭或 ctx 取消；ctx 派生子 trace（父 id.workerID），该 worker 的日志可与其他 worker 区分。
 func (p *Pool) runWorker(ctx context.Context, workerID int) {
+	ctx = trace.WithSubTraceID(ctx, strconv.Itoa(workerID))
+	trace.Debug(ctx, "worker: 启动")
+	defer trace.Debug(ctx, "worker: 退出")
 	for {
 		select {
 		case <-ctx.Done():
@@ -211,13 +468,10 @@ func (p *Pool) runWorker(ctx context.Context, workerID int) {
 			if !ok {
 				return
 			}
-			stock := p.fetchAndMerge(ctx, &q)
+			stock := p.process(ctx, &q)
 			if stock == nil {
 				continue
 			}
-			if !p.filter(stock) {
-				continue
-			}
 			select {
 			case <-ctx.Done():
 				return
@@ -227,41 +481,208 @@ func (p *Pool) runWorker(ctx context.Context, workerID int) {
 	}
 }
 
+// process 拉 K 线、算指标并过滤，未通过返回 nil。单只股票因脏数据 panic 时记录代码与堆栈后跳过，
+// 不让该 worker 退出，保证整轮继续处理其余股票。
+func (p *Pool) process(ctx context.Context, q *model.StockQuote) (stock *model.Stock) {
+	p.stats.processed.Add(1)
+	fetched := false
+	defer func() {
+		if r := recover(); r != nil {
+			trace.Error(ctx, "worker: panic code=%s err=%v stack=%s", q.Code, r, debug.Stack())
+			p.recordFailure(q, ReasonPanic, fmt.Errorf("panic: %v", r))
+			stock = nil
+			// 补全前 panic 计为拉取失败，补全后（过滤、打分、补概况）panic 计为淘汰
+			if fetched {
+				p.stats.filtered.Add(1)
+			} else {
+				p.stats.klineFailed.Add(1)
+			}
+		}
+	}()
+	stock = p.cfg.Cache.get(q.Code, func() *model.Stock { return p.fetchAndMerge(ctx, q) })
+	if stock == nil {
+		p.stats.klineFailed.Add(1)
+		return nil
+	}
+	fetched = true
+	p.stats.fetched.Add(1)
+	if !p.filter(stock) {
+		p.stats.filtered.Add(1)
+		return nil
+	}
+	if p.cfg.Scorer != nil {
+		stock.Score = p.cfg.Scorer(stock)
+	}
+	if p.cfg.FillProfile {
+		fillProfile(ctx, p.api, stock)
+	}
+	p.stats.selected.Add(1)
+	return stock
+}
+
+// fetchKlines 拉 K 线：网络错误（api.ErrRequest）稍后重试；无数据/空 K 线是确定结果，直接返回。
+func (p *Pool) fetchKlines(ctx context.Context, code string) ([]model.KLine, error) {
+	klines, err := p.api.GetHisKlines(ctx, code, p.cfg.KlineCount)
+	for retry := 0; retry < klineFetchRetries && errors.Is(err, api.ErrRequest) && ctx.Err() == nil; retry++ {
+		trace.Log(ctx, "worker: GetHisKlines code=%s 网络错误，%s 后重试 err=%v", code, klineRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(klineRetryDelay):
+		}
+		klines, err = p.api.GetHisKlines(ctx, code, p.cfg.KlineCount)
+	}
+	return klines, err
+}
+
 func (p *Pool) fetchAndMerge(ctx context.Context, q *model.StockQuote) *model.Stock {
-	klines, err := p.api.GetHisKlines(ctx, q.Code, klineCountForStrategy)
-	if err != nil {
+	klines, err := p.fetchKlines(ctx, q.Code)
+	switch {
+	case errors.Is(err, api.ErrNoData), errors.Is(err, api.ErrEmptyKlines):
+		trace.Log(ctx, "worker: code=%s 无 K 线数据（停牌/新股/代码无效），跳过 err=%v", q.Code, err)
+		p.recordFailure(q, ReasonNoData, err)
+		return nil
+	case err != nil:
 		trace.Log(ctx, "worker: GetHisKlines code=%s err=%v", q.Code, err)
+		p.recordFailure(q, ReasonFetch, err)
 		return nil
 	}
 	if len(klines) < minKlinesForMA20 {
 		trace.Log(ctx, "worker: klines<%d code=%s", minKlinesForMA20, q.Code)
+		p.recordFailure(q, ReasonInsufficient, nil)
+		return nil
+	}
+	deviation := priceDeviation(q.Price, klines)
+	if math.Abs(deviation) > p.cfg.PriceDeviationWarnPct {
+		trace.Warn(ctx, "worker: code=%s 现价 %.2f 与最新 K 线(%s)收盘 %.2f 偏差 %.2f%%，行情与 K 线可能不同步",
+			q.Code, q.Price, klines[len(klines)-1].Date, klines[len(klines)-1].Close, deviation)
+	}
+	s := Merge(q, klines, p.cfg.IncludeSuspendedVolume)
+	flow := p.fundFlow(ctx, q.Code)
+	s.PriceDeviationPct = deviation
+	s.FundFlowDays, s.MainNetInflowSum, s.MainNetInflowStreak = flow.days, flow.sum, flow.streak
+	p.fillMoneyFlow(ctx, s)
+	return s
+}
+
+// fillMoneyFlow 按 Config.MoneyFlow 用资金流专用接口覆盖主力资金字段并清除缺失标记；拉取失败时保留列表数据，只记日志。
+func (p *Pool) fillMoneyFlow(ctx context.Context, s *model.Stock) {
+	if !p.cfg.MoneyFlow {
+		return
+	}
+	mf, err := p.api.GetMoneyFlow(ctx, s.Code)
+	if err != nil {
+		trace.Log(ctx, "worker: GetMoneyFlow code=%s err=%v，沿用列表资金字段 missing=%v", s.Code, err, s.MoneyFlowMissing)
+		return
+	}
+	s.NetInflow, s.MainForceInflow, s.MainForceOutflow = mf.MainNetInflow, mf.MainInflow, mf.MainOutflow
+	s.MoneyFlowMissing = false
+}
+
+// Merge 用行情与 K 线计算指标合并为 Stock，最后一根 K 线视为“当前”（回测时传入截至历史某日的切片即可复用）；
+// 不含资金流与行情偏差（由 Pool 补充）。K 线不足 minKlinesForMA20 根返回 nil。
+func Merge(q *model.StockQuote, klines []model.KLine, includeSuspendedVolume bool) *model.Stock {
+	if len(klines) < minKlinesForMA20 {
 		return nil
 	}
 	// 同一 slice 滑动计算，不重复请求：MA5/10/20/60、MA60 趋势、MACD 均从 klines 推导
 	ma60Now := maNAt(klines, 60, 0)
 	ma60Prev := maNAt(klines, 60, ma60TrendLookback)
+	maCross := ma20CrossUpMA60(klines)
 	macd := computeMACD(klines)
+	// 均量取最新一根之前的 K 线，放量判断时与当日量比较不被当日量本身稀释
+	last := klines[len(klines)-1]
+	volKlines := volumeKlines(klines[:len(klines)-1], includeSuspendedVolume)
+	suspendedDays := len(klines) - len(volumeKlines(klines, false))
+	highN, drawdown := drawdownFromHigh(klines, q.Price, drawdownLookback)
+	boll := computeBoll(klines, bollPeriod, bollWidth)
+	atr := computeATR(klines, atrPeriod14)
+	highDays := newHighDays(klines)
 	return &model.Stock{
-		Code:              q.Code,
-		Name:              q.Name,
-		MainBusiness:      q.MainBusiness,
-		Price:             q.Price,
-		MA5:               MA5(klines),
-		MA10:              MA10(klines),
-		MA20:              MA20(klines),
-		MA60:              ma60Now,
-		ChangePct:         q.ChangePct,
-		Amount:            q.Amount,
-		VolumeRatio:       q.VolumeRatio,
-		TurnoverRate:      q.TurnoverRate,
-		MarketCap:         q.MarketCap,
-		PE:                q.PE,
-		NetInflow:         q.NetInflow,
-		MainForceInflow:   q.MainForceInflow,
-		MainForceOutflow:  q.MainForceOutflow,
-		MA60Up:            ma60Prev > 0 && ma60Now > ma60Prev,
-		MacdHistogram:     macd.histogram,
-		MacdHistogramPrev: macd.histogramPrev,
-		MacdGoldenCross:   macd.goldenCross,
+		Code:                q.Code,
+		Name:                q.Name,
+		MainBusiness:        q.MainBusiness,
+		Price:               q.Price,
+		MA5:                 MA5(klines),
+		MA10:                MA10(klines),
+		MA20:                MA20(klines),
+		MA60:                ma60Now,
+		MA5Valid:            len(klines) >= maPeriod5,
+		MA10Valid:           len(klines) >= maPeriod10,
+		MA20Valid:           len(klines) >= maPeriod20,
+		MA60Valid:           len(klines) >= maPeriod60,
+		ChangePct:           q.ChangePct,
+		Amount:              q.Amount,
+		VolumeRatio:         q.VolumeRatio,
+		TurnoverRate:        q.TurnoverRate,
+		MarketCap:           q.MarketCap,
+		PE:                  q.PE,
+		NetInflow:           q.NetInflow,
+		MainForceInflow:     q.MainForceInflow,
+		MainForceOutflow:    q.MainForceOutflow,
+		MoneyFlowMissing:    q.NetInflow == 0 && q.MainForceInflow == 0 && q.MainForceOutflow == 0,
+		MA60Up:              ma60Prev > 0 && ma60Now > ma60Prev,
+		MA20CrossUpMA60:     maCross,
+		MacdHistogram:       macd.histogram,
+		MacdHistogramPrev:   macd.histogramPrev,
+		MacdGoldenCross:     macd.goldenCross,
+		RSI14:               computeRSI(klines, rsiPeriod14),
+		BollUpper:           boll.upper,
+		BollMid:             boll.mid,
+		BollLower:           boll.lower,
+		ATR14:               atr,
+		ATRPct:              atrPct(atr, q.Price),
+		UpStreak:            upStreak(klines),
+		NewHighDays:         highDays,
+		IsNewHigh:           highDays >= newHighLookback,
+		PB:                  q.PB,
+		ROE:                 q.ROE,
+		RevenueGrowth:       q.RevenueGrowth,
+		ProfitGrowth:        q.ProfitGrowth,
+		Industry:            q.Industry,
+		IndustryChangePct:   q.IndustryChangePct,
+		IndustryRank:        q.IndustryRank,
+		SuspendedDays:       suspendedDays,
+		Volume:              last.Volume,
+		VolMA5:              volumeMA(volKlines, maPeriod5),
+		HighN:               highN,
+		DrawdownFromHigh:    drawdown,
+		PreFilterRank:       q.PreFilterRank,
+		TopConcept:          q.TopConcept,
+		TopConceptChangePct: q.TopConceptChangePct,
+	}
+}
+
+// fundFlowResult 近 N 日主力资金汇总：实际天数、净流入之和、截至最近一日的连续净流入天数。
+type fundFlowResult struct {
+	days   int
+	sum    float64
+	streak int
+}
+
+// fundFlow 按 Config.FundFlowDays 拉资金流并汇总；未开启或拉取失败返回零值（相关条件放行）。
+func (p *Pool) fundFlow(ctx context.Context, code string) fundFlowResult {
+	if p.cfg.FundFlowDays <= 0 {
+		return fundFlowResult{}
+	}
+	flows, err := p.api.GetFundFlowHistory(ctx, code, p.cfg.FundFlowDays)
+	if err != nil {
+		trace.Log(ctx, "worker: GetFundFlowHistory code=%s err=%v，资金流条件放行", code, err)
+		return fundFlowResult{}
+	}
+	return summarizeFundFlow(flows, p.cfg.FundFlowDays)
+}
+
+func summarizeFundFlow(flows []model.FundFlowDay, n int) fundFlowResult {
+	if len(flows) > n {
+		flows = flows[len(flows)-n:]
+	}
+	r := fundFlowResult{days: len(flows)}
+	for _, f := range flows {
+		r.sum += f.MainNetInflow
+	}
+	for i := len(flows) - 1; i >= 0 && flows[i].MainNetInflow > 0; i-- {
+		r.streak++
 	}
+	return r
 }
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"math"
+	"testing"
+
+	"stockMaxWin/internal/model"
+)
+
+const floatTolerance = 1e-9
+
+func closes(vs ...float64) []model.KLine {
+	ks := make([]model.KLine, len(vs))
+	for i, v := range vs {
+		ks[i] = model.KLine{Close: v, High: v, Low: v}
+	}
+	return ks
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < floatTolerance
+}
+
+func TestComputeRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		klines []model.KLine
+		period int
+		want   float64
+	}{
+		// 涨跌 +1 -0.5 +1 -0.5：首个均值 gain=2/3 loss=1/6；第 4 日平滑后 gain=4/9 loss=5/18，RS=1.6
+		{"wilder", closes(10, 11, 10.5, 11.5, 11), 3, 100 - 100/2.6},
+		{"不足 period+1 根", closes(10, 11, 12), 3, 0},
+		{"全程无下跌", closes(10, 11, 11, 12), 3, 100},
+		{"全程持平", closes(10, 10, 10, 10, 10), 3, 50},
+		{"period 无效", closes(10, 11, 12), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeRSI(tt.klines, tt.period); !almostEqual(got, tt.want) {
+				t.Errorf("computeRSI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeBoll(t *testing.T) {
+	tests := []struct {
+		name   string
+		klines []model.KLine
+		period int
+		width  float64
+		want   bollResult
+	}{
+		// 只取最近 3 根 2,4,6：均值 4，样本方差 (4+0+4)/2=4，标准差 2
+		{"手算", closes(100, 2, 4, 6), 3, 2, bollResult{upper: 8, mid: 4, lower: 0}},
+		{"恰好 period 根", closes(1, 2, 3), 3, 1, bollResult{upper: 3, mid: 2, lower: 1}},
+		{"不足 period 根", closes(2, 4), 3, 2, bollResult{}},
+		{"period 小于 2", closes(2, 4, 6), 1, 2, bollResult{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeBoll(tt.klines, tt.period, tt.width)
+			if !almostEqual(got.upper, tt.want.upper) || !almostEqual(got.mid, tt.want.mid) || !almostEqual(got.lower, tt.want.lower) {
+				t.Errorf("computeBoll() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// atrKlines 前收 10；跳空高开（TR 取 |最高-前收|=2）、跳空低开（TR 取 |最低-前收|=2.8）、区间内（TR 取 最高-最低=0.6）
+var atrKlines = []model.KLine{
+	{Close: 10, High: 10.5, Low: 9.5},
+	{Close: 11.8, High: 12, Low: 11.5},
+	{Close: 9.2, High: 10, Low: 9},
+	{Close: 9.4, High: 9.6, Low: 9},
+}
+
+func TestTrueRange(t *testing.T) {
+	tests := []struct {
+		name string
+		i    int
+		want float64
+	}{
+		{"跳空高开", 1, 2},
+		{"跳空低开", 2, 2.8},
+		{"区间内", 3, 0.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trueRange(atrKlines[tt.i], atrKlines[tt.i-1]); !almostEqual(got, tt.want) {
+				t.Errorf("trueRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeATR(t *testing.T) {
+	tests := []struct {
+		name   string
+		klines []model.KLine
+		period int
+		want   float64
+	}{
+		// 首个 ATR=(2+2.8)/2=2.4，再平滑 (2.4*1+0.6)/2=1.5
+		{"wilder", atrKlines, 2, 1.5},
+		{"恰好 period+1 根", atrKlines[:3], 2, 2.4},
+		{"不足 period+1 根", atrKlines[:2], 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeATR(tt.klines, tt.period); !almostEqual(got, tt.want) {
+				t.Errorf("computeATR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/llm"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// llmCommentTimeout 点评生成的等待上限，超时即省略点评，不拖慢推送
+const llmCommentTimeout = 30 * time.Second
+
+// commenter 可选的 LLM 点评，配置了 Ollama 模型时启用；nil 表示不点评。
+var commenter llm.Commenter
+
+func buildCommenter() llm.Commenter {
+	c := config.LoadLLM()
+	if !c.Enabled() {
+		return nil
+	}
+	return llm.NewOllama(llm.OllamaConfig{Endpoint: c.OllamaEndpoint, Model: c.OllamaModel, Timeout: llmCommentTimeout})
+}
+
+// llmComment 生成本轮点评；未启用、无入选或 LLM 不可用时返回空串，只记日志。
+func llmComment(ctx context.Context, stocks []*model.Stock) string {
+	if commenter == nil || len(stocks) == 0 {
+		return ""
+	}
+	cctx, cancel := context.WithTimeout(ctx, llmCommentTimeout)
+	defer cancel()
+	text, err := commenter.Comment(cctx, stocks)
+	if err != nil {
+		trace.Log(ctx, "main: LLM 点评不可用，邮件省略点评 err=%v", err)
+		return ""
+	}
+	trace.Log(ctx, "main: LLM 点评=%s", text)
+	return text
+}
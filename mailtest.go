@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/mail"
+	"stockMaxWin/internal/trace"
+)
+
+// runMailTestCommand stockMaxWin mailtest：用当前 SMTP 配置连接、认证并发一封测试邮件，逐步打印结果。
+func runMailTestCommand() int {
+	ctx := trace.WithTraceID(context.Background(), trace.NewTraceID())
+	cfg := buildMailConfig(config.LoadSMTP())
+	fmt.Printf("SMTP 配置：server=%s port=%d user=%s from=%s to=%s 密码=%s\n",
+		cfg.Server, cfg.Port, cfg.User, cfg.From, cfg.To, maskSecret(cfg.Password))
+	for i, b := range cfg.Backups {
+		fmt.Printf("备用 SMTP %d：server=%s port=%d user=%s 密码=%s\n", i+1, b.Server, b.Port, b.User, maskSecret(b.Password))
+	}
+	err := mail.SendTestMail(ctx, cfg, func(step string, err error) {
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", step, err)
+			return
+		}
+		fmt.Printf("  ✓ %s\n", step)
+	})
+	if err != nil {
+		fmt.Printf("测试失败：%v\n", err)
+		return 1
+	}
+	fmt.Printf("测试邮件已发送到 %s，请检查收件箱（含垃圾箱）\n", cfg.To)
+	return 0
+}
+
+// maskSecret 只显示是否已配置，不回显密码/授权码。
+func maskSecret(s string) string {
+	if s == "" {
+		return "(未配置)"
+	}
+	return "******"
+}
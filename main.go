@@ -4,28 +4,63 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net/http"
 	"os"
-	"sort"
+	"os/signal"
 	"strconv"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"stockMaxWin/internal/api"
+	"stockMaxWin/internal/buildinfo"
+	"stockMaxWin/internal/calendar"
 	"stockMaxWin/internal/config"
-	"stockMaxWin/internal/filter"
 	"stockMaxWin/internal/mail"
 	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/notify"
+	"stockMaxWin/internal/pipeline"
+	"stockMaxWin/internal/proxy"
 	"stockMaxWin/internal/trace"
 	"stockMaxWin/internal/worker"
 )
 
 // 环境变量名（便于维护与文档）
 const (
-	envConcurrency = "STOCKMAXWIN_CONCURRENCY"
-	envSchedule    = "STOCKMAXWIN_SCHEDULE"
+	envSchedule      = "STOCKMAXWIN_SCHEDULE"
+	envLogTraceDir   = "STOCKMAXWIN_LOG_TRACE_DIR"
+	envLogSinks      = "STOCKMAXWIN_LOG_SINKS"
+	envLogLevel      = "STOCKMAXWIN_LOG_LEVEL"   // 日志输出阈值 debug/info/warn/error，默认 debug 全部输出
+	envLogFile       = "STOCKMAXWIN_LOG_FILE"    // 额外写入的日志文件，按天切割为 文件名.日期
+	envRandomSeed    = "STOCKMAXWIN_RANDOM_SEED" // 固定邮件格言/加油话的随机种子，便于复现
+	envHolidays      = "STOCKMAXWIN_HOLIDAYS_FILE"
+	envKlineCacheDir = "STOCKMAXWIN_KLINE_CACHE_DIR" // 日 K 磁盘增量缓存目录，除权时自动整只重拉
 )
 
+// defaultHolidaysFile 休市日文件默认路径，存在时追加到内置交易日历
+const defaultHolidaysFile = "holidays.json"
+
+// loadHolidays 把 holidays.json（或 STOCKMAXWIN_HOLIDAYS_FILE）中的休市日/补班日追加到默认交易日历；默认文件不存在时静默跳过。
+func loadHolidays() {
+	path := os.Getenv(envHolidays)
+	explicit := path != ""
+	if !explicit {
+		path = defaultHolidaysFile
+	}
+	err := calendar.Default().LoadFile(path)
+	switch {
+	case err == nil:
+		log.Printf("已加载休市日 %s", path)
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+	default:
+		log.Printf("休市日文件加载失败，仅用内置交易日历: %v", err)
+	}
+}
+
 // 运行与超时
 const (
 	runTimeout       = 10 * time.Minute
@@ -40,16 +75,9 @@ const (
 
 // 选股结果与提醒
 const (
- 	topNByChangePct         = 10
+	defaultTopN             = 10 // 入选结果默认取前 N，STOCKMAXWIN_TOP_N 可改
 	emptyRunsBeforeReminder = 3
-)
-
-// 调度时间（本地时区，周一至周五）
-const (
-	scheduleMarketOpen   = 9
-	scheduleMarketClose  = 15
-	scheduleFirstMinute  = 15
-	scheduleSlotInterval = 30
+	failedRunsBeforeAlert   = 3
 )
 
 // 日志时间格式
@@ -58,15 +86,66 @@ const timeFormatNextRun = "2006-01-02 15:04"
 // 初选预分配容量系数（candidates 约 len(quotes)/candidateCapDiv）
 const candidateCapDiv = 4
 
+// runtimeConcurrency 当前 worker 并发数，启动与 SIGHUP 重载时由 applyRuntimeConfig 更新。
+var runtimeConcurrency atomic.Int64
+
 func concurrency() int {
-	if s := os.Getenv(envConcurrency); s != "" {
-		if n, err := strconv.Atoi(s); err == nil && n > 0 {
-			return n
-		}
+	if n := runtimeConcurrency.Load(); n > 0 {
+		return int(n)
 	}
 	return defaultConcurrency
 }
 
+// applyRuntimeConfig 重新读取配置文件与环境变量，更新 worker 并发与 API 限流；
+// 进行中的一轮不受影响，下一轮 runOnce 新建的 Pool 按新并发度运行。
+func applyRuntimeConfig(ctx context.Context) {
+	rc := config.LoadRuntime()
+	n := rc.Concurrency
+	if n <= 0 {
+		n = defaultConcurrency
+	}
+	runtimeConcurrency.Store(int64(n))
+	var rps float64 // 0：未配置，保持当前（默认令牌桶）
+	switch {
+	case rc.APIRPS == 0:
+		rps = api.RPSFixedGap
+	case rc.APIRPS > 0:
+		rps = rc.APIRPS
+	}
+	lim := api.SetLimits(api.Limits{
+		RPS:           rps,
+		Burst:         rc.APIBurst,
+		RequestGap:    time.Duration(rc.APIDelayMS) * time.Millisecond,
+		JitterMS:      rc.APIJitterMS,
+		MaxConcurrent: rc.APIMaxConcurrent,
+	})
+	ttl := api.DefaultKlineTTL
+	if rc.KlineTTLMinutes >= 0 {
+		ttl = time.Duration(rc.KlineTTLMinutes) * time.Minute
+	}
+	apiClient.SetKlineTTL(ttl)
+	pacing := fmt.Sprintf("api_rps=%g api_burst=%d", lim.RPS, lim.Burst)
+	if lim.RPS <= 0 {
+		pacing = fmt.Sprintf("api_gap=%s api_jitter=%dms", lim.RequestGap, lim.JitterMS)
+	}
+	trace.Log(ctx, "main: 运行参数 concurrency=%d %s api_max_concurrent=%d kline_ttl=%s",
+		n, pacing, lim.MaxConcurrent, ttl)
+}
+
+// watchReload 收到 SIGHUP 时重载运行参数与休市日文件，调度模式下无需重启即可调整并发、限流、K 线缓存有效期，
+// 以及追加新年度休市日。休市日只合并新增日期，从文件中删掉的日期要重启才会失效。
+func watchReload(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			trace.Log(ctx, "main: 收到 SIGHUP，重载运行参数与休市日")
+			applyRuntimeConfig(ctx)
+			loadHolidays()
+		}
+	}()
+}
+
 func scheduleEnabled() bool {
 	s := os.Getenv(envSchedule)
 	return s == "true" || s == "1"
@@ -74,8 +153,135 @@ func scheduleEnabled() bool {
 
 var apiClient = api.NewClient()
 
+// httpTransport 按代理规则构建的共享 Transport，行情接口与推送渠道共用；nil 为默认 Transport。
+var httpTransport http.RoundTripper
+
+// configureProxy 按 STOCKMAXWIN_PROXY / _PROXY_DOMAINS / _NO_PROXY（或配置文件）设置代理规则；未配置代理地址时沿用系统环境变量。
+func configureProxy() {
+	pc := config.LoadProxy()
+	rules, err := proxy.Parse(pc.URL, pc.Domains, pc.NoProxy)
+	if err != nil {
+		log.Printf("代理地址无效，忽略代理配置: %v", err)
+		return
+	}
+	if rules.URL != nil {
+		log.Printf("代理 %s，走代理域名=%v，直连域名=%v", rules.URL.Redacted(), rules.Domains, rules.NoProxy)
+	}
+	t := rules.Transport()
+	httpTransport = t
+	apiClient.HTTPClient.Transport = t
+}
+
+// configureEndpoints 按 STOCKMAXWIN_API_*_URL（或配置文件 api_*_url）覆盖行情接口地址，无效时保持默认地址。
+func configureEndpoints() {
+	ec := config.LoadAPIEndpoints()
+	err := apiClient.SetEndpoints(api.Endpoints{List: ec.ListURL, KLine: ec.KLineURL, Index: ec.IndexURL})
+	if err != nil {
+		log.Printf("行情接口地址无效，使用默认地址: %v", err)
+		return
+	}
+	if ec.ListURL != "" || ec.KLineURL != "" || ec.IndexURL != "" {
+		log.Printf("行情接口地址 list=%s kline=%s index=%s", apiClient.ListURL, apiClient.KLineURL, apiClient.IndexURL)
+	}
+}
+
+// configureKlineCache 按 STOCKMAXWIN_KLINE_CACHE_DIR 开启日 K 磁盘增量缓存，目录不可用时不缓存。
+func configureKlineCache() {
+	dir := os.Getenv(envKlineCacheDir)
+	if dir == "" {
+		return
+	}
+	if err := apiClient.SetKlineCacheDir(dir); err != nil {
+		log.Printf("K 线缓存目录不可用，不缓存: %v", err)
+		return
+	}
+	log.Printf("日 K 增量缓存 %s", dir)
+}
+
+// notifiers 邮件以外的推送渠道，启动时按配置构建一次（企业微信应用需跨轮复用 access_token 缓存）。
+var notifiers []notify.Notifier
+
+func buildNotifiers() []notify.Notifier {
+	var ns []notify.Notifier
+	if w := config.LoadWeComApp(); w.Enabled() {
+		ns = append(ns, notify.NewWeComAppNotifier(notify.WeComAppConfig{
+			CorpID:     w.CorpID,
+			CorpSecret: w.CorpSecret,
+			AgentID:    w.AgentID,
+			ToUser:     w.ToUser,
+			MsgType:    w.MsgType,
+			Transport:  httpTransport,
+		}))
+	}
+	wh := config.LoadWebhooks()
+	if c := (notify.WebhookConfig{URL: wh.FeishuURL, Secret: wh.FeishuSecret, Transport: httpTransport}); c.Enabled() {
+		ns = append(ns, notify.NewFeishuNotifier(c))
+	}
+	if c := (notify.WebhookConfig{URL: wh.DingTalkURL, Secret: wh.DingTalkSecret, Transport: httpTransport}); c.Enabled() {
+		ns = append(ns, notify.NewDingTalkNotifier(c))
+	}
+	if c := (notify.WebhookConfig{URL: wh.WeComURL, Transport: httpTransport}); c.Enabled() {
+		ns = append(ns, notify.NewWeChatWorkNotifier(c))
+	}
+	for _, n := range ns {
+		log.Printf("已启用推送渠道 %s", n.Name())
+	}
+	return ns
+}
+
 func main() {
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+	if len(os.Args) > 1 && (os.Args[1] == "version" || os.Args[1] == "--version") {
+		fmt.Println("stockMaxWin " + buildinfo.String())
+		return
+	}
+	log.Printf("stockMaxWin %s 启动", buildinfo.String())
+	configureProxy()
+	configureEndpoints()
+	configureKlineCache()
+	if spec := os.Getenv(envLogSinks); spec != "" {
+		hs, err := trace.ParseSinks(spec)
+		if err != nil {
+			log.Printf("日志 sink 配置无效，沿用默认输出: %v", err)
+		} else {
+			trace.SetHandlers(hs...)
+		}
+	}
+	if s := os.Getenv(envLogLevel); s != "" {
+		if l, ok := trace.ParseLevel(s); ok {
+			trace.SetLevel(l)
+		} else {
+			log.Printf("%s=%q 无效，沿用默认级别 debug", envLogLevel, s)
+		}
+	}
+	if path := os.Getenv(envLogFile); path != "" {
+		if h, err := trace.NewDailyFileHandler(path, trace.LevelDebug, trace.FormatText); err != nil {
+			log.Printf("日志文件未开启: %v", err)
+		} else {
+			trace.AddHandler(h)
+		}
+	}
+	if s := os.Getenv(envRandomSeed); s != "" {
+		if seed, err := strconv.ParseInt(s, 10, 64); err == nil {
+			mail.SetRand(rand.New(rand.NewSource(seed)))
+		} else {
+			log.Printf("%s 无效，使用默认随机源: %v", envRandomSeed, err)
+		}
+	}
+	loadHolidays()
+	if err := trace.SetTraceDir(os.Getenv(envLogTraceDir)); err != nil {
+		log.Printf("按 trace 分文件日志未开启: %v", err)
+	}
+	applyRuntimeConfig(trace.WithTraceID(context.Background(), trace.NewTraceID()))
+	// 子命令（sweep、backtest 等）同样走并发与限流配置、日志设置与休市日历，因此在以上初始化之后再分发
+	if len(os.Args) > 1 {
+		if code, ok := runCommand(os.Args[1:]); ok {
+			os.Exit(code)
+		}
+	}
+	notifiers = buildNotifiers()
+	marketGate = buildMarketGate()
+	commenter = buildCommenter()
 	// 启动成功时向收件人发一封打招呼邮件：今日大盘 + 随机加油语
 	mailCfg := buildMailConfig(config.LoadSMTP())
 	if mailCfg.Enabled() {
@@ -92,165 +298,208 @@ func main() {
 		}
 	}
 	if scheduleEnabled() {
-		log.Printf("[调度] 已开启定时模式：9:15 / 9:45 / … / 15:00 每半小时执行（周一至周五），进程将常驻")
-		runScheduler()
+		log.Printf("[调度] 已开启定时模式：%s（交易日），进程将常驻", loadSchedule().describe())
+		runScheduler(context.Background())
 		return
 	}
+	os.Exit(runSingle())
+}
+
+// runSingle 单次模式：跑一轮、写状态文件后返回退出码（失败为 1）。
+// 所有 context 在返回前取消，worker 与 HTTP 请求随之结束，进程干净退出。
+func runSingle() int {
 	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
 	defer cancel()
-	_ = runOnce(ctx)
+	res := runOnce(ctx)
+	writeStatusIfEnabled(trace.WithTraceID(ctx, res.TraceID), res)
+	// 关闭空闲 keep-alive 连接，避免退出前还挂着读连接的 goroutine
+	apiClient.HTTPClient.CloseIdleConnections()
+	if res.Err != nil {
+		return 1
+	}
+	return 0
 }
 
-// runScheduler 常驻进程：每半小时 9:15~15:00（周一至周五）执行一次，保证按指定时间周期一直执行。
-// 连续 emptyRunsBeforeReminder 次无入选时发送提醒邮件（请好好工作 + 随机炒股格言）。
-func runScheduler() {
+// runCommand 处理子命令，返回退出码；非子命令参数返回 ok=false，按常规选股流程运行。
+func runCommand(args []string) (code int, ok bool) {
+	switch args[0] {
+	case "stats":
+		return runStatsCommand(args[1:]), true
+	case "mailtest":
+		return runMailTestCommand(), true
+	case "sweep":
+		return runSweepCommand(args[1:]), true
+	case "backtest":
+		return runBacktestCommand(args[1:]), true
+	case "show-defaults", "--show-defaults":
+		return runShowDefaultsCommand(), true
+	}
+	return 0, false
+}
+
+// runScheduler 常驻进程：按调度时间段（默认每半小时 9:15~15:00，交易日）执行，保证按指定时间周期一直执行。
+// 连续竞价时段内连续 emptyRunsBeforeReminder 次无入选时发送提醒邮件（请好好工作 + 随机炒股格言），之后静默到出现入选为止
+// （见 noSelectionAlert，非交易时段的无入选不计入）；
+// 运行失败（如行情拉取失败）不计入无入选，连续 failedRunsBeforeAlert 次失败时另发“程序异常”告警邮件。
+// 收到 SIGINT/SIGTERM 时：等待中立即返回；运行中则取消当前轮（runCtx 派生自 ctx），该轮收尾后返回，便于 k8s/systemd 平滑停止。
+func runScheduler(ctx context.Context) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	traceID := trace.NewTraceID()
-	ctx := trace.WithTraceID(context.Background(), traceID)
-	trace.Log(ctx, "main: 调度模式启动，每半小时 9:15~15:00 周一至周五")
-	var emptyRunCount int
+	ctx = trace.WithTraceID(ctx, traceID)
+	sched := loadSchedule()
+	trace.Log(ctx, "main: 调度模式启动，%s 交易日（跳过周末与休市日）", sched.describe())
+	watchReload(ctx)
+	stopHTTP := startHTTPServerIfEnabled(ctx)
+	defer stopHTTP()
+	var failedRunCount int
+	var emptyAlert noSelectionAlert
+	var pool dailyPool
 	for {
-		next := nextRunTime()
-		now := time.Now()
+		now := clock()
+		next := sched.nextRunTime(now)
 		if next.After(now) {
 			d := next.Sub(now)
 			log.Printf("[调度] 下次执行时间：%s（约 %s 后）", next.Format(timeFormatNextRun), d.Round(time.Second))
 			trace.Log(ctx, "main: 下次执行 %s (约 %s 后)", next.Format(timeFormatNextRun), d.Round(time.Second))
-			time.Sleep(d)
+			if !sleepCtx(ctx, d) {
+				trace.Log(ctx, "main: 等待中收到退出信号，调度结束 err=%v", ctx.Err())
+				return
+			}
+		}
+		if !calendar.IsTradingDay(clock()) {
+			// 等待期间休市日可能已更新（SIGHUP 重载 holidays.json），到点再确认一次，非交易日不跑也不计入无入选
+			trace.Log(ctx, "main: %s 非交易日，跳过本轮", clock().Format(dailyPoolDayFormat))
+			continue
 		}
-		runCtx, cancel := context.WithTimeout(context.Background(), runTimeout)
+		runCtx, cancel := context.WithTimeout(ctx, runTimeout)
 		runCtx = trace.WithTraceID(runCtx, trace.NewTraceID())
-		selected := runOnce(runCtx)
+		res := runOnce(runCtx)
 		cancel()
-		if len(selected) == 0 {
-			emptyRunCount++
-			if emptyRunCount >= emptyRunsBeforeReminder {
-				trace.Log(ctx, "main: 连续 %d 次无入选，发送提醒邮件", emptyRunCount)
+		if ctx.Err() != nil {
+			trace.Log(ctx, "main: 运行中收到退出信号，已取消本轮 trace=%s 并结束调度 err=%v", res.TraceID, ctx.Err())
+			return
+		}
+		recentRuns.add(res)
+		pool.add(res)
+		pool.sendSummaryIfClosed(ctx, sched, res)
+		if res.Err != nil {
+			failedRunCount++
+			trace.Log(ctx, "main: 本轮运行失败（连续 %d 次）err=%v", failedRunCount, res.Err)
+			if failedRunCount >= failedRunsBeforeAlert {
 				mailCfg := buildMailConfig(config.LoadSMTP())
-				if err := mail.SendNoSelectionReminder(context.Background(), mailCfg); err != nil {
-					trace.Log(ctx, "main: 发送提醒邮件失败 err=%v", err)
+				if err := mail.SendFailureAlert(context.Background(), mailCfg, failedRunCount, res.Err); err != nil {
+					trace.Log(ctx, "main: 发送异常告警邮件失败 err=%v", err)
 				} else {
-					trace.Log(ctx, "main: 已发提醒邮件，请好好工作")
+					trace.Log(ctx, "main: 已发程序异常告警邮件")
 				}
-				emptyRunCount = 0
+				failedRunCount = 0
 			}
-		} else {
-			emptyRunCount = 0
+			continue
 		}
-	}
-}
-
-// nextRunTime 返回下次应执行时刻（本地时区，周一至周五 9:15/9:45/.../15:00）
-func nextRunTime() time.Time {
-	loc := time.Local
-	now := time.Now().In(loc)
-	slots := buildScheduleSlots()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
-	minutesSinceMidnight := now.Hour()*60 + now.Minute()
-	isWeekday := now.Weekday() != time.Sunday && now.Weekday() != time.Saturday
-
-	if isWeekday {
-		for _, slotMin := range slots {
-			if minutesSinceMidnight < slotMin {
-				return dayStart.Add(time.Duration(slotMin) * time.Minute)
-			}
+		failedRunCount = 0
+		if emptyAlert.observe(ctx, res) {
+			sendNoSelectionReminder(ctx, emptyAlert.count)
 		}
 	}
-	return nextWeekdayAt(now, loc, scheduleMarketOpen, scheduleFirstMinute)
 }
 
-func buildScheduleSlots() []int {
-	var slots []int
-	for h := scheduleMarketOpen; h < scheduleMarketClose; h++ {
-		slots = append(slots, h*60+scheduleFirstMinute, h*60+scheduleFirstMinute+scheduleSlotInterval)
+// sleepCtx 等待 d 或 ctx 取消，正常等满返回 true。
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
 	}
-	slots = append(slots, scheduleMarketClose*60+0)
-	return slots
 }
 
-func nextWeekdayAt(from time.Time, loc *time.Location, hour, min int) time.Time {
-	next := from
-	for {
-		next = next.AddDate(0, 0, 1)
-		if next.Weekday() != time.Sunday && next.Weekday() != time.Saturday {
-			break
-		}
-	}
-	return time.Date(next.Year(), next.Month(), next.Day(), hour, min, 0, 0, loc)
+// RunResult 一轮选股的结果与漏斗统计，供复盘报告与调度使用。
+type RunResult struct {
+	TraceID     string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Indices     []model.IndexQuote // 大盘指数，仅开启复盘报告时拉取
+	Quotes      int                // 行情数（主板或全市场，见 STOCKMAXWIN_SCAN_MODE）
+	Candidates  int                // 初选通过数（请求 K 线的数量）
+	Passed      int                // 技术面过滤通过数（截断前）
+	Failures    []worker.Failure   // 补全指标时被丢弃的股票及原因（无数据、拉取失败、K 线不足等）
+	Selected    []*model.Stock
+	Err         error             // 运行失败（行情拉取失败、流水线中止等），区别于正常无入选
+	Timings     []pipeline.Timing // 各阶段耗时：拉列表、流水线各阶段、收尾（报告/导出/历史）
+	CallAuction bool              // 运行于集合竞价时段，行情为竞价数据而非连续竞价成交
+	GateClosed  string            // 大盘择时关闸原因，非空时本轮跳过选股
+	Stats       worker.Stats      // 补全与策略过滤统计：处理、拉到 K 线、拉取失败、淘汰、通过
 }
 
-func runOnce(ctx context.Context) []*model.Stock {
+// 耗时打点中流水线之外的阶段名
+const (
+	timingList = "list"
+	timingPost = "post"
+)
+
+func runOnce(ctx context.Context) RunResult {
 	ctx = trace.WithTraceID(ctx, trace.NewTraceID())
+	res := RunResult{TraceID: trace.TraceID(ctx), StartedAt: clock()}
+	res.CallAuction = inCallAuction(res.StartedAt)
 	trace.Log(ctx, "main: start")
-	quotes, err := apiClient.GetMainBoardQuotes(ctx)
+	if res.CallAuction {
+		trace.Log(ctx, "main: 当前处于集合竞价时段，行情为竞价数据（非连续竞价成交）")
+	}
+	if open, reason, indices := checkMarketGate(ctx); !open {
+		trace.Log(ctx, "main: 大盘择时关闸（%s），跳过本轮选股，空仓观望", reason)
+		res.Indices = indices
+		res.GateClosed = reason
+		sendMarketGateNotice(ctx, reason, indices)
+		res.FinishedAt = clock()
+		return res
+	}
+	listStart := time.Now()
+	quotes, err := fetchQuotes(ctx)
+	res.Timings = append(res.Timings, pipeline.Timing{Name: timingList, Duration: time.Since(listStart)})
 	if err != nil {
-		trace.Log(ctx, "main: GetMainBoardQuotes err=%v", err)
-		log.Printf("GetMainBoardQuotes: %v", err)
-		return nil
+		trace.Log(ctx, "main: 拉取%s行情 err=%v", scanLabel(), err)
+		log.Printf("fetch %s quotes: %v", scanMode(), err)
+		res.Err = err
+		res.FinishedAt = clock()
+		return res
 	}
 	if quotes == nil {
 		quotes = []model.StockQuote{}
 	}
-	candidates := make([]model.StockQuote, 0, len(quotes)/candidateCapDiv)
-	for i := range quotes {
-		if filter.QuotePreFilter(&quotes[i]) {
-			candidates = append(candidates, quotes[i])
-		}
+	st := &pipeline.State{Quotes: quotes}
+	p := buildPipeline(ctx, &res)
+	if err := p.Run(ctx, st); err != nil {
+		trace.Log(ctx, "main: pipeline 中止 err=%v", err)
+		res.Err = err
 	}
-	trace.Log(ctx, "main: 初选 主板 %d 只 -> 基本面+成交量 %d 只，仅对后者请求 K 线", len(quotes), len(candidates))
-
-	nConc := concurrency()
-	jobs := make(chan model.StockQuote, jobChannelBuffer)
-	results := make(chan *model.Stock, jobChannelBuffer)
-	cfg := worker.DefaultConfig()
-	cfg.Concurrency = nConc
-	cfg.Filter = func(s *model.Stock) bool { return filter.TrendMomentumStrategy()(s) }
-	pool := worker.NewPool(cfg, apiClient, jobs, results)
-
-	var selected []*model.Stock
-	done := make(chan struct{})
-	go func() {
-		for s := range results {
-			if s == nil {
-				continue
-			}
-			selected = append(selected, s)
-			fmt.Fprintf(os.Stdout, "%s %s 主营=%s 现价=%.2f 涨跌幅=%.2f%%\n",
-				s.Code, s.Name, s.MainBusiness, s.Price, s.ChangePct)
-		}
-		close(done)
-	}()
-
-	go pool.Run(ctx)
-
-	for i := range candidates {
-		select {
-		case <-ctx.Done():
-			trace.Log(ctx, "main: ctx done, produced %d jobs", i)
-			goto done
-		case jobs <- candidates[i]:
-		}
-	}
-done:
-	close(jobs)
-	<-done
-
-	sort.Slice(selected, func(i, j int) bool {
-		return selected[i].ChangePct > selected[j].ChangePct
-	})
-	if len(selected) > topNByChangePct {
-		selected = selected[:topNByChangePct]
-	}
-	trace.Log(ctx, "main: 选股完成，按涨幅取前 %d 只, 发邮件", len(selected))
-	mailCfg := buildMailConfig(config.LoadSMTP())
-	mail.MustSendReport(ctx, mailCfg, selected)
-	trace.Log(ctx, "main: end, 共 %d 只", len(selected))
-	return selected
+	res.Timings = append(res.Timings, st.Timings...)
+	res.Selected = st.Stocks
+	res.FinishedAt = clock()
+	postStart := time.Now()
+	writeReportIfEnabled(ctx, &res)
+	writeExportIfEnabled(ctx, res)
+	writeCSVIfEnabled(ctx, res)
+	appendHistoryIfEnabled(ctx, res)
+	saveStoreIfEnabled(ctx, res)
+	res.Timings = append(res.Timings, pipeline.Timing{Name: timingPost, Duration: time.Since(postStart)})
+	trace.Log(ctx, "main: 补全统计 processed=%d fetched=%d kline_failed=%d filtered=%d selected=%d",
+		res.Stats.Processed, res.Stats.Fetched, res.Stats.KlineFailed, res.Stats.Filtered, res.Stats.Selected)
+	trace.Log(ctx, "main: 耗时汇总 %s", pipeline.FormatTimings(res.Timings))
+	trace.Log(ctx, "main: end, 共 %d 只", len(res.Selected))
+	return res
 }
 
 func buildMailConfig(smtpCfg *config.SMTP) *mail.SMTPConfig {
 	if smtpCfg == nil {
 		smtpCfg = &config.SMTP{}
 	}
+	backups := make([]mail.SMTPServer, 0, len(smtpCfg.Servers))
+	for _, srv := range smtpCfg.Servers {
+		backups = append(backups, mail.SMTPServer(srv))
+	}
 	return &mail.SMTPConfig{
 		Server:   smtpCfg.Server,
 		Port:     smtpCfg.Port,
@@ -258,9 +507,22 @@ func buildMailConfig(smtpCfg *config.SMTP) *mail.SMTPConfig {
 		Password: smtpCfg.Password,
 		From:     smtpCfg.From,
 		To:       smtpCfg.To,
+		Theme:    buildMailTheme(config.LoadMailTheme()),
+		Backups:  backups,
 	}
 }
 
+// buildMailTheme 内置主题叠加配置中的单项覆盖（品牌色等）。
+func buildMailTheme(t *config.MailTheme) mail.Theme {
+	return mail.ThemeByName(t.Name).Merge(mail.Theme{
+		Primary:    t.Primary,
+		Up:         t.Up,
+		Down:       t.Down,
+		Background: t.Background,
+		Font:       t.Font,
+	})
+}
+
 func GetAllStocks(ctx context.Context) ([]model.StockBrief, error) {
 	return apiClient.GetAllStocks(ctx)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/mail"
+	"stockMaxWin/internal/trace"
+)
+
+// emptyReason 无入选的原因：非交易时段（集合竞价、午休、盘前盘后）行情不完整，无入选属正常，不计入提醒；
+// 连续竞价时段内仍无入选视为策略偏严。
+type emptyReason string
+
+const (
+	emptyOffHours       emptyReason = "off_hours"
+	emptyStrategyStrict emptyReason = "strategy_strict"
+)
+
+func (r emptyReason) label() string {
+	if r == emptyOffHours {
+		return "非交易时段"
+	}
+	return "策略偏严"
+}
+
+// noSelectionAlert 无入选提醒状态机：连续竞价时段内累计 emptyRunsBeforeReminder 次无入选发一次提醒并进入静默，
+// 静默期间不再提醒，直到出现一次入选才恢复计数，避免策略长期过严时周期性重复轰炸。
+type noSelectionAlert struct {
+	count    int
+	silenced bool
+}
+
+// observe 记录一轮结果，需要发提醒时返回 true。运行失败的轮次由调用方单独处理，不应传入。
+func (a *noSelectionAlert) observe(ctx context.Context, res RunResult) bool {
+	if len(res.Selected) > 0 {
+		if a.silenced {
+			trace.Log(ctx, "main: 出现入选，无入选提醒解除静默")
+		}
+		a.count, a.silenced = 0, false
+		return false
+	}
+	if res.GateClosed != "" {
+		trace.Log(ctx, "main: 本轮大盘择时关闸，不计入无入选提醒")
+		return false
+	}
+	reason := emptyStrategyStrict
+	if !inContinuousSession(res.StartedAt) {
+		reason = emptyOffHours
+	}
+	if reason == emptyOffHours {
+		trace.Log(ctx, "main: 本轮无入选（%s），不计入提醒", reason.label())
+		return false
+	}
+	if a.silenced {
+		trace.Log(ctx, "main: 本轮无入选（%s），已提醒过，静默至出现入选", reason.label())
+		return false
+	}
+	a.count++
+	trace.Log(ctx, "main: 本轮无入选（%s），连续 %d 次", reason.label(), a.count)
+	if a.count < emptyRunsBeforeReminder {
+		return false
+	}
+	a.silenced = true
+	return true
+}
+
+// sendNoSelectionReminder 发无入选提醒邮件，失败只记日志。
+func sendNoSelectionReminder(ctx context.Context, runs int) {
+	trace.Log(ctx, "main: 连续 %d 次无入选，发送提醒邮件并进入静默", runs)
+	mailCfg := buildMailConfig(config.LoadSMTP())
+	if err := mail.SendNoSelectionReminder(context.Background(), mailCfg); err != nil {
+		trace.Log(ctx, "main: 发送提醒邮件失败 err=%v", err)
+		return
+	}
+	trace.Log(ctx, "main: 已发提醒邮件，请好好工作")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/worker"
+)
+
+// 排序环境变量：
+// STOCKMAXWIN_SORT_BY 排序维度 change_pct（默认，按涨幅）、turnover_rate（换手）、volume_ratio（量比）、net_inflow（主力净流入）、score（多因子评分）；
+// STOCKMAXWIN_SORT_ORDER=asc 升序（如看跌幅榜），默认 desc 降序；
+// STOCKMAXWIN_TOP_N 取前 N 只，默认 defaultTopN。
+const (
+	envSortBy    = "STOCKMAXWIN_SORT_BY"
+	envSortOrder = "STOCKMAXWIN_SORT_ORDER"
+	envTopN      = "STOCKMAXWIN_TOP_N"
+	sortOrderAsc = "asc"
+)
+
+type sortKey string
+
+const (
+	sortByChangePct   sortKey = "change_pct"
+	sortByTurnover    sortKey = "turnover_rate"
+	sortByVolumeRatio sortKey = "volume_ratio"
+	sortByNetInflow   sortKey = "net_inflow"
+	sortByScore       sortKey = "score"
+)
+
+// sortKeyFromEnv 读取排序维度，未配置或无法识别时按涨幅。
+func sortKeyFromEnv() sortKey {
+	switch k := sortKey(strings.ToLower(strings.TrimSpace(os.Getenv(envSortBy)))); k {
+	case sortByTurnover, sortByVolumeRatio, sortByNetInflow, sortByScore:
+		return k
+	default:
+		return sortByChangePct
+	}
+}
+
+// sortDescFromEnv 是否降序，只有显式配置 asc 时升序。
+func sortDescFromEnv() bool {
+	return !strings.EqualFold(strings.TrimSpace(os.Getenv(envSortOrder)), sortOrderAsc)
+}
+
+// topNFromEnv 取前 N 只，未配置或无效时为 defaultTopN。
+func topNFromEnv() int {
+	if s := os.Getenv(envTopN); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultTopN
+}
+
+// label 返回用于日志与邮件标题的排序说明，升序时注明。
+func (k sortKey) label(desc bool) string {
+	name := "涨幅"
+	switch k {
+	case sortByTurnover:
+		name = "换手率"
+	case sortByVolumeRatio:
+		name = "量比"
+	case sortByNetInflow:
+		name = "主力净流入"
+	case sortByScore:
+		name = "综合评分"
+	}
+	if !desc {
+		return "按" + name + "升序排序"
+	}
+	return "按" + name + "排序"
+}
+
+// value 返回排序依据的数值。
+func (k sortKey) value(s *model.Stock) float64 {
+	switch k {
+	case sortByTurnover:
+		return s.TurnoverRate
+	case sortByVolumeRatio:
+		return s.VolumeRatio
+	case sortByNetInflow:
+		return mainForceNet(s)
+	case sortByScore:
+		return s.Score
+	}
+	return s.ChangePct
+}
+
+// mainForceNet 主力净流入：优先用列表接口的 NetInflow，缺失时用主力流入-流出。
+func mainForceNet(s *model.Stock) float64 {
+	if s.NetInflow != 0 {
+		return s.NetInflow
+	}
+	return s.MainForceInflow - s.MainForceOutflow
+}
+
+// scorer 按评分排序时 worker 使用的评分函数，其它排序维度不打分。
+func (k sortKey) scorer() worker.ScoreFunc {
+	if k == sortByScore {
+		return worker.MultiFactorScore
+	}
+	return nil
+}
+
+// rankStocks 按 key 排序（desc 为降序，相同值保持原顺序）后取前 n 只，n<=0 不截断；返回新切片，不修改入参。
+func rankStocks(stocks []*model.Stock, key sortKey, desc bool, n int) []*model.Stock {
+	out := append([]*model.Stock(nil), stocks...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if desc {
+			return key.value(out[i]) > key.value(out[j])
+		}
+		return key.value(out[i]) < key.value(out[j])
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"stockMaxWin/internal/model"
+)
+
+// rankFixture A、D 在各维度上取值相同，用于验证相同值保持原顺序；D 的净流入取主力流入-流出。
+func rankFixture() []*model.Stock {
+	return []*model.Stock{
+		{Code: "A", ChangePct: 5, TurnoverRate: 3, VolumeRatio: 2, NetInflow: 2e8, Score: 60},
+		{Code: "B", ChangePct: 9, TurnoverRate: 1, VolumeRatio: 3, NetInflow: 1e8, Score: 80},
+		{Code: "C", ChangePct: -2, TurnoverRate: 8, VolumeRatio: 1, NetInflow: -1e8, Score: 40},
+		{Code: "D", ChangePct: 5, TurnoverRate: 3, VolumeRatio: 2, MainForceInflow: 3e8, MainForceOutflow: 1e8, Score: 60},
+	}
+}
+
+func codesOf(stocks []*model.Stock) []string {
+	out := make([]string, len(stocks))
+	for i, s := range stocks {
+		out[i] = s.Code
+	}
+	return out
+}
+
+func TestRankStocks(t *testing.T) {
+	tests := []struct {
+		name string
+		key  sortKey
+		desc bool
+		n    int
+		want []string
+	}{
+		{"涨幅降序", sortByChangePct, true, 0, []string{"B", "A", "D", "C"}},
+		{"涨幅升序", sortByChangePct, false, 0, []string{"C", "A", "D", "B"}},
+		{"换手降序", sortByTurnover, true, 0, []string{"C", "A", "D", "B"}},
+		{"换手升序", sortByTurnover, false, 0, []string{"B", "A", "D", "C"}},
+		{"量比降序", sortByVolumeRatio, true, 0, []string{"B", "A", "D", "C"}},
+		{"量比升序", sortByVolumeRatio, false, 0, []string{"C", "A", "D", "B"}},
+		{"净流入降序", sortByNetInflow, true, 0, []string{"A", "D", "B", "C"}},
+		{"净流入升序", sortByNetInflow, false, 0, []string{"C", "B", "A", "D"}},
+		{"评分降序", sortByScore, true, 0, []string{"B", "A", "D", "C"}},
+		{"评分升序", sortByScore, false, 0, []string{"C", "A", "D", "B"}},
+		{"取前 2", sortByChangePct, true, 2, []string{"B", "A"}},
+		{"n 为负不截断", sortByChangePct, true, -1, []string{"B", "A", "D", "C"}},
+		{"n 超过长度不截断", sortByChangePct, true, 10, []string{"B", "A", "D", "C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := rankFixture()
+			got := codesOf(rankStocks(in, tt.key, tt.desc, tt.n))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankStocks() = %v, want %v", got, tt.want)
+			}
+			if orig := codesOf(in); !reflect.DeepEqual(orig, []string{"A", "B", "C", "D"}) {
+				t.Errorf("入参被修改: %v", orig)
+			}
+		})
+	}
+}
+
+func TestRankStocksEmpty(t *testing.T) {
+	if got := rankStocks(nil, sortByChangePct, true, 5); len(got) != 0 {
+		t.Errorf("rankStocks(nil) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// 复盘报告：STOCKMAXWIN_REPORT_DIR 非空时每轮结束写当日 Markdown 报告（同日多轮覆盖为最新一轮）
+const (
+	envReportDir         = "STOCKMAXWIN_REPORT_DIR"
+	reportFileDateFormat = "2006-01-02"
+	reportTimeFormat     = "2006-01-02 15:04:05"
+	reportFilePerm       = 0o644
+	reportDirPerm        = 0o755
+)
+
+// writeReportIfEnabled 开启复盘报告时补拉大盘指数并写出报告，失败只记日志。
+func writeReportIfEnabled(ctx context.Context, res *RunResult) {
+	dir := os.Getenv(envReportDir)
+	if dir == "" {
+		return
+	}
+	indices, err := apiClient.GetIndexQuotes(ctx)
+	if err != nil {
+		trace.Log(ctx, "main: 复盘报告获取大盘数据失败(仍写报告) err=%v", err)
+	}
+	res.Indices = indices
+	path, err := writeMarkdownReport(dir, *res)
+	if err != nil {
+		trace.Log(ctx, "main: 写复盘报告失败 err=%v", err)
+		return
+	}
+	trace.Log(ctx, "main: 已写复盘报告 %s", path)
+}
+
+// writeMarkdownReport 在 dir 下写 复盘-日期.md，返回文件路径。内容为大盘概况、入选表与淘汰漏斗，可直接粘进笔记软件。
+func writeMarkdownReport(dir string, res RunResult) (string, error) {
+	if err := os.MkdirAll(dir, reportDirPerm); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "复盘-"+res.StartedAt.Format(reportFileDateFormat)+".md")
+	if err := os.WriteFile(path, []byte(buildMarkdownReport(res)), reportFilePerm); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func buildMarkdownReport(res RunResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# 选股复盘 %s\n\n", res.StartedAt.Format(reportFileDateFormat))
+	fmt.Fprintf(&b, "> 运行时间 %s ~ %s，TRACE=%s\n\n",
+		res.StartedAt.Format(reportTimeFormat), res.FinishedAt.Format(reportTimeFormat), res.TraceID)
+	if res.CallAuction {
+		b.WriteString("> **集合竞价数据**：本轮运行于集合竞价时段，价格与涨幅为竞价撮合结果，非连续竞价成交。\n\n")
+	}
+
+	b.WriteString("## 大盘概况\n\n")
+	if len(res.Indices) == 0 {
+		b.WriteString("（未获取到大盘数据）\n\n")
+	} else {
+		b.WriteString("| 指数 | 现价 | 涨跌幅 |\n|---|---:|---:|\n")
+		for _, q := range res.Indices {
+			fmt.Fprintf(&b, "| %s | %.2f | %.2f%% |\n", escapeMarkdownCell(q.Name), q.Price, q.ChangePct)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## 入选股票\n\n")
+	if len(res.Selected) == 0 {
+		b.WriteString("本轮无入选。\n\n")
+	} else {
+		b.WriteString("| 代码 | 名称 | 行业 | 现价 | 涨幅% | MA20 | MA60 | 换手% | 量比 | MACD红柱 |\n")
+		b.WriteString("|---|---|---|---:|---:|---:|---:|---:|---:|---:|\n")
+		for _, s := range res.Selected {
+			if s == nil {
+				continue
+			}
+			industry := s.Industry
+			if industry == "" {
+				industry = "-"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f | %.3f |\n",
+				s.Code, escapeMarkdownCell(s.Name), escapeMarkdownCell(industry), s.Price, s.ChangePct,
+				s.MA20, s.MA60, s.TurnoverRate, s.VolumeRatio, s.MacdHistogram)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## 淘汰漏斗\n\n")
+	fmt.Fprintf(&b, "- %s行情：%d 只\n", scanLabel(), res.Quotes)
+	fmt.Fprintf(&b, "- 初选（基本面+成交量）通过：%d 只\n", res.Candidates)
+	if len(res.Failures) > 0 {
+		fmt.Fprintf(&b, "- 补全指标失败：%d 只（%s）\n", len(res.Failures), worker.FormatFailureCounts(res.Failures))
+	}
+	fmt.Fprintf(&b, "- 技术面过滤通过：%d 只\n", res.Passed)
+	fmt.Fprintf(&b, "- 排序取前 N 后入选：%d 只\n", len(res.Selected))
+	if len(res.Failures) > 0 {
+		b.WriteString("\n## 补全指标失败\n\n| 代码 | 名称 | 原因 | 错误 |\n|------|------|------|------|\n")
+		for _, f := range res.Failures {
+			errMsg := "-"
+			if f.Err != nil {
+				errMsg = f.Err.Error()
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Code, escapeMarkdownCell(f.Name), f.Reason, escapeMarkdownCell(errMsg))
+		}
+	}
+	return b.String()
+}
+
+// escapeMarkdownCell 避免名称中的 | 打乱表格。
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 扫描范围：
+// STOCKMAXWIN_BOARDS 为逗号分隔的板块（main/chinext/star/bse），按板块分别拉取行情后合并，默认仅主板；
+// STOCKMAXWIN_SCAN_MODE=all 时改用全市场行情（GetAllQuotes），此时板块限定由初选负责：
+// STOCKMAXWIN_PREFILTER_MAIN_BOARD=1 仅保留主板代码（默认不限板块）。
+const (
+	envBoards             = "STOCKMAXWIN_BOARDS"
+	envScanMode           = "STOCKMAXWIN_SCAN_MODE"
+	envPreFilterMainBoard = "STOCKMAXWIN_PREFILTER_MAIN_BOARD"
+	scanModeBoards        = "boards"
+	scanModeAll           = "all"
+)
+
+var defaultBoards = []model.Board{model.BoardMain}
+
+func scanMode() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv(envScanMode)), scanModeAll) {
+		return scanModeAll
+	}
+	return scanModeBoards
+}
+
+// scanBoards 按板块扫描时拉取的板块，未配置或配置无效时仅主板。
+func scanBoards() []model.Board {
+	boards, err := model.ParseBoards(os.Getenv(envBoards))
+	if err != nil || len(boards) == 0 {
+		return defaultBoards
+	}
+	return boards
+}
+
+// preFilterBoards 初选的板块限定：全市场扫描且开启 STOCKMAXWIN_PREFILTER_MAIN_BOARD 时仅主板，否则不限。
+func preFilterBoards() []model.Board {
+	s := os.Getenv(envPreFilterMainBoard)
+	if scanMode() == scanModeAll && (s == "1" || s == "true") {
+		return []model.Board{model.BoardMain}
+	}
+	return nil
+}
+
+// scanLabel 日志中的扫描范围名称，如“主板”“主板+创业板”“全市场”“自选股”。
+func scanLabel() string {
+	if watchlistEnabled() {
+		return "自选股"
+	}
+	if scanMode() == scanModeAll {
+		return "全市场"
+	}
+	boards := scanBoards()
+	labels := make([]string, len(boards))
+	for i, b := range boards {
+		labels[i] = b.Label()
+	}
+	return strings.Join(labels, "+")
+}
+
+// fetchQuotes 按扫描范围拉取本轮行情列表；多板块时单个板块失败只记日志，全部失败才返回错误。
+// 配置了自选股时只查自选股行情（见 fetchWatchlistQuotes）。
+func fetchQuotes(ctx context.Context) ([]model.StockQuote, error) {
+	if watchlistEnabled() {
+		return fetchWatchlistQuotes(ctx)
+	}
+	if scanMode() == scanModeAll {
+		return apiClient.GetAllQuotes(ctx)
+	}
+	if v := os.Getenv(envBoards); v != "" {
+		if _, err := model.ParseBoards(v); err != nil {
+			trace.Log(ctx, "main: %s 无效，仅扫描主板 err=%v", envBoards, err)
+		}
+	}
+	boards := scanBoards()
+	var list []model.StockQuote
+	var lastErr error
+	for _, b := range boards {
+		part, err := apiClient.GetBoardQuotes(ctx, b)
+		if err != nil {
+			lastErr = err
+			trace.Log(ctx, "main: 拉取%s行情失败，跳过 err=%v", b.Label(), err)
+			continue
+		}
+		list = append(list, part...)
+	}
+	if list == nil && lastErr != nil {
+		return nil, fmt.Errorf("all boards failed: %w", lastErr)
+	}
+	return list, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"stockMaxWin/internal/calendar"
+	"stockMaxWin/internal/config"
+)
+
+// 默认调度时间（A 股，本地时区，交易日：周一至周五且非休市日，见 internal/calendar）：9:15 起每 30 分钟，收盘 15:00 再跑一次
+const (
+	scheduleMarketOpen   = 9
+	scheduleFirstMinute  = 15
+	scheduleMarketClose  = 15
+	scheduleCloseMinute  = 0
+	scheduleSlotInterval = 30
+)
+
+// 集合竞价时段：9:15 开始竞价，9:25 撮合后到 9:30 连续竞价开始前，行情仍是竞价结果而非连续竞价成交
+const (
+	callAuctionStartMinute = 9*60 + 15
+	callAuctionEndMinute   = 9*60 + 30
+)
+
+// inCallAuction 判断 t（本地时区）是否处于交易日集合竞价时段 [9:15, 9:30)，此时拉到的行情为竞价数据。
+func inCallAuction(t time.Time) bool {
+	if !calendar.IsTradingDay(t) {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	return m >= callAuctionStartMinute && m < callAuctionEndMinute
+}
+
+// 连续竞价时段：上午 9:30~11:30、下午 13:00~15:00（含 15:00 收盘这一刻，收盘数据即当日最终行情）
+const (
+	morningSessionEndMinute     = 11*60 + 30
+	afternoonSessionStartMinute = 13 * 60
+	afternoonSessionEndMinute   = 15 * 60
+)
+
+// inContinuousSession 判断 t 是否处于交易日连续竞价时段；集合竞价、午间休市、盘前盘后与非交易日均为 false。
+func inContinuousSession(t time.Time) bool {
+	if !calendar.IsTradingDay(t) {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	return (m >= callAuctionEndMinute && m < morningSessionEndMinute) ||
+		(m >= afternoonSessionStartMinute && m <= afternoonSessionEndMinute)
+}
+
+// clock 当前时间来源，测试或回放时可替换为固定时钟以得到确定的调度结果。
+var clock = time.Now
+
+// scheduleConfig 调度时间段：开盘首个执行点、收盘执行点（均为本地时区时:分）与间隔分钟。
+type scheduleConfig struct {
+	openHour, openMinute   int
+	closeHour, closeMinute int
+	interval               int
+}
+
+var defaultSchedule = scheduleConfig{
+	openHour:    scheduleMarketOpen,
+	openMinute:  scheduleFirstMinute,
+	closeHour:   scheduleMarketClose,
+	closeMinute: scheduleCloseMinute,
+	interval:    scheduleSlotInterval,
+}
+
+// loadSchedule 读取调度时间段（环境变量优先于配置文件），未配置的字段用 A 股默认值；
+// 配置非法（时分越界、间隔<=0、收盘不晚于开盘）时整体回退默认并记日志。
+func loadSchedule() scheduleConfig {
+	c := config.LoadSchedule()
+	s := defaultSchedule
+	for _, f := range []struct {
+		src int
+		dst *int
+	}{
+		{c.OpenHour, &s.openHour},
+		{c.OpenMinute, &s.openMinute},
+		{c.CloseHour, &s.closeHour},
+		{c.CloseMinute, &s.closeMinute},
+		{c.IntervalMinutes, &s.interval},
+	} {
+		if f.src != -1 {
+			*f.dst = f.src
+		}
+	}
+	if err := s.validate(); err != nil {
+		log.Printf("[调度] 调度配置无效（%v），回退默认 %s", err, defaultSchedule.describe())
+		return defaultSchedule
+	}
+	return s
+}
+
+// validate 校验时分范围、间隔为正且收盘执行点晚于开盘首个执行点。
+func (s scheduleConfig) validate() error {
+	if s.openHour < 0 || s.openHour > 23 || s.closeHour < 0 || s.closeHour > 23 {
+		return fmt.Errorf("小时须在 0~23：open=%d close=%d", s.openHour, s.closeHour)
+	}
+	if s.openMinute < 0 || s.openMinute > 59 || s.closeMinute < 0 || s.closeMinute > 59 {
+		return fmt.Errorf("分钟须在 0~59：open=%d close=%d", s.openMinute, s.closeMinute)
+	}
+	if s.interval <= 0 {
+		return fmt.Errorf("间隔须大于 0：interval=%d", s.interval)
+	}
+	if s.closeHour*60+s.closeMinute <= s.openHour*60+s.openMinute {
+		return fmt.Errorf("收盘 %d:%02d 须晚于开盘 %d:%02d", s.closeHour, s.closeMinute, s.openHour, s.openMinute)
+	}
+	return nil
+}
+
+// describe 用于日志：如“9:15~15:00 每 30 分钟”。
+func (s scheduleConfig) describe() string {
+	return fmt.Sprintf("%d:%02d~%d:%02d 每 %d 分钟", s.openHour, s.openMinute, s.closeHour, s.closeMinute, s.interval)
+}
+
+// nextRunTime 返回 now 之后下次应执行时刻（now 所在时区，交易日各 slot），节假日与周末整天跳过。
+func (s scheduleConfig) nextRunTime(now time.Time) time.Time {
+	loc := now.Location()
+	slots := s.buildScheduleSlots()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	minutesSinceMidnight := now.Hour()*60 + now.Minute()
+	if calendar.IsTradingDay(now) {
+		for _, slotMin := range slots {
+			if minutesSinceMidnight < slotMin {
+				return dayStart.Add(time.Duration(slotMin) * time.Minute)
+			}
+		}
+	}
+	return nextTradingDayAt(now, loc, s.openHour, s.openMinute)
+}
+
+// buildScheduleSlots 从开盘首个执行点起按间隔生成当日各执行点（距零点分钟数），最后补上收盘执行点。
+func (s scheduleConfig) buildScheduleSlots() []int {
+	open := s.openHour*60 + s.openMinute
+	closeAt := s.closeHour*60 + s.closeMinute
+	var slots []int
+	for m := open; m < closeAt; m += s.interval {
+		slots = append(slots, m)
+	}
+	slots = append(slots, closeAt)
+	return slots
+}
+
+// nextTradingDayAt 返回 from 之后第一个交易日的 hour:min。
+func nextTradingDayAt(from time.Time, loc *time.Location, hour, min int) time.Time {
+	next := calendar.NextTradingDay(from)
+	return time.Date(next.Year(), next.Month(), next.Day(), hour, min, 0, 0, loc)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// cst 北京时间；用固定时区而非 time.Local，测试结果与运行机器时区无关。
+var cst = time.FixedZone("CST", 8*3600)
+
+// fakeClock 把 clock 替换为固定时刻，测试结束恢复。
+func fakeClock(t *testing.T, now time.Time) {
+	t.Helper()
+	orig := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = orig })
+}
+
+func at(date string, hour, min int) time.Time {
+	d, err := time.ParseInLocation("2006-01-02", date, cst)
+	if err != nil {
+		panic(err)
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), hour, min, 0, 0, cst)
+}
+
+func TestNextRunTime(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"盘前到首个执行点", at("2025-11-18", 8, 0), at("2025-11-18", 9, 15)},
+		{"盘中到下一个 slot", at("2025-11-18", 10, 0), at("2025-11-18", 10, 15)},
+		{"恰在 slot 上取下一个", at("2025-11-18", 10, 15), at("2025-11-18", 10, 45)},
+		{"收盘前最后一段到 15:00", at("2025-11-18", 14, 50), at("2025-11-18", 15, 0)},
+		{"周五收盘后跨周末", at("2025-11-14", 15, 30), at("2025-11-17", 9, 15)},
+		{"周六全天跳过", at("2025-11-15", 10, 0), at("2025-11-17", 9, 15)},
+		{"国庆长假前收盘后", at("2025-09-30", 15, 1), at("2025-10-09", 9, 15)},
+		{"休市日当天盘中", at("2026-10-01", 10, 0), at("2026-10-08", 9, 15)},
+		{"中秋周五休市连周末", at("2026-09-24", 15, 1), at("2026-09-28", 9, 15)},
+		{"调休补班的周六不开市", at("2026-10-09", 15, 1), at("2026-10-12", 9, 15)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClock(t, tt.now)
+			if got := defaultSchedule.nextRunTime(clock()); !got.Equal(tt.want) {
+				t.Errorf("nextRunTime(%s) = %s, want %s", tt.now.Format(timeFormatNextRun), got.Format(timeFormatNextRun), tt.want.Format(timeFormatNextRun))
+			}
+		})
+	}
+}
+
+func TestInCallAuctionWithClock(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"交易日 9:20", at("2025-11-18", 9, 20), true},
+		{"交易日 9:30 已连续竞价", at("2025-11-18", 9, 30), false},
+		{"休市日 9:20", at("2025-10-01", 9, 20), false},
+		{"周末 9:20", at("2025-11-15", 9, 20), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClock(t, tt.now)
+			if got := inCallAuction(clock()); got != tt.want {
+				t.Errorf("inCallAuction = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
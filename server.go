@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"stockMaxWin/internal/buildinfo"
+	"stockMaxWin/internal/export"
+	"stockMaxWin/internal/feed"
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// REST server 模式：STOCKMAXWIN_HTTP_ADDR（如 :8080）非空时，调度模式下同时提供 HTTP 接口：
+// GET /feed.xml 最近几轮选股结果的 Atom feed，每轮一个 entry；
+// GET /status 版本与构建信息、进程启动时间及最近一轮概况（JSON）；
+// GET /latest 最近一轮的入选股票（JSON），手机浏览器可直接查看；
+// GET /health 存活探针，进程在跑即返回 200。
+const (
+	envHTTPAddr       = "STOCKMAXWIN_HTTP_ADDR"
+	feedMaxEntries    = 20
+	feedID            = "urn:stockmaxwin:feed"
+	feedTitle         = "stockMaxWin 选股结果"
+	feedAuthor        = "stockMaxWin"
+	feedEntryIDPrefix = "urn:stockmaxwin:run:"
+	feedTimeFormat    = "2006-01-02 15:04"
+	feedEmptyCell     = "-"
+	httpReadTimeout   = 10 * time.Second
+	httpWriteTimeout  = 30 * time.Second
+	httpShutdownWait  = 5 * time.Second
+)
+
+// feedDefaultColumns feed 表格默认列，STOCKMAXWIN_MAIL_FIELDS 同样生效
+var feedDefaultColumns = []string{"code", "name", "price", "change_pct", "top_concept", "main_business"}
+
+// runHistory 保存最近 feedMaxEntries 轮结果（新在前），供 HTTP 接口读取。
+type runHistory struct {
+	mu   sync.Mutex
+	runs []RunResult
+}
+
+var recentRuns = &runHistory{}
+
+func (h *runHistory) add(res RunResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append([]RunResult{res}, h.runs...)
+	if len(h.runs) > feedMaxEntries {
+		h.runs = h.runs[:feedMaxEntries]
+	}
+}
+
+func (h *runHistory) list() []RunResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]RunResult(nil), h.runs...)
+}
+
+// startHTTPServerIfEnabled 配置了监听地址时在后台启动 HTTP 服务，监听失败只记日志。
+// 返回的 stop 用于随调度退出优雅关闭：不再接新连接，等待进行中的请求最多 httpShutdownWait；未启动时为空操作。
+func startHTTPServerIfEnabled(ctx context.Context) (stop func()) {
+	addr := strings.TrimSpace(os.Getenv(envHTTPAddr))
+	if addr == "" {
+		return func() {}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", handleFeed)
+	mux.HandleFunc("/status", handleStatus)
+	mux.HandleFunc("/latest", handleLatest)
+	mux.HandleFunc("/health", handleHealth)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadTimeout: httpReadTimeout, WriteTimeout: httpWriteTimeout}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		trace.Log(ctx, "main: HTTP 服务已启动 addr=%s", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("HTTP 服务退出: %v", err)
+		}
+	}()
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownWait)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			trace.Log(ctx, "main: HTTP 服务关闭超时 err=%v", err)
+		}
+		<-done
+		trace.Log(ctx, "main: HTTP 服务已关闭")
+	}
+}
+
+// processStartedAt 进程启动时间，/status 展示
+var processStartedAt = time.Now()
+
+type statusRun struct {
+	TraceID    string    `json:"trace_id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Selected   int       `json:"selected"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// statusResponse /status 输出：版本信息、进程启动时间、最近一轮概况，以及内置默认阈值与当前生效阈值。
+type statusResponse struct {
+	buildinfo.Info
+	StartedAt         time.Time              `json:"started_at"`
+	LastRun           *statusRun             `json:"last_run,omitempty"`
+	DefaultThresholds []filter.ThresholdInfo `json:"default_thresholds"`
+	Thresholds        []filter.ThresholdInfo `json:"thresholds"`
+}
+
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	resp := statusResponse{
+		Info:              buildinfo.Get(),
+		StartedAt:         processStartedAt,
+		DefaultThresholds: filter.DefaultThresholds().Describe(),
+		Thresholds:        strategyThresholds(r.Context()).Describe(),
+	}
+	if runs := recentRuns.list(); len(runs) > 0 {
+		last := runs[0]
+		resp.LastRun = &statusRun{TraceID: last.TraceID, StartedAt: last.StartedAt, FinishedAt: last.FinishedAt, Selected: len(last.Selected)}
+		if last.Err != nil {
+			resp.LastRun.Error = last.Err.Error()
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("status: 输出失败 err=%v", err)
+	}
+}
+
+// latestResponse /latest 输出：最近一轮的时间、入选股票；该轮失败或关闸时给出原因。
+type latestResponse struct {
+	TraceID    string         `json:"trace_id"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Error      string         `json:"error,omitempty"`
+	GateClosed string         `json:"gate_closed,omitempty"`
+	Selected   []*model.Stock `json:"selected"`
+}
+
+func handleLatest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	runs := recentRuns.list()
+	if len(runs) == 0 {
+		http.Error(w, "no run yet", http.StatusNotFound)
+		return
+	}
+	last := runs[0]
+	resp := latestResponse{
+		TraceID:    last.TraceID,
+		StartedAt:  last.StartedAt,
+		FinishedAt: last.FinishedAt,
+		GateClosed: last.GateClosed,
+		Selected:   last.Selected,
+	}
+	if resp.Selected == nil {
+		resp.Selected = []*model.Stock{}
+	}
+	if last.Err != nil {
+		resp.Error = last.Err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("latest: 输出失败 err=%v", err)
+	}
+}
+
+// handleHealth 存活探针：只说明进程与 HTTP 服务在跑，不检查行情接口，避免外部抖动导致探针重启进程。
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "started_at": processStartedAt}); err != nil {
+		log.Printf("health: 输出失败 err=%v", err)
+	}
+}
+
+func handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	f := feed.Feed{ID: feedID, Title: feedTitle, Author: feedAuthor, Link: "http://" + r.Host + r.URL.Path}
+	for _, res := range recentRuns.list() {
+		f.Entries = append(f.Entries, feedEntry(res))
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	if err := f.WriteAtom(w); err != nil {
+		log.Printf("feed: 输出失败 err=%v", err)
+	}
+}
+
+// feedEntry 一轮结果转为 entry：标题含时间与入选数，内容为股票列表 HTML；失败轮次给出错误原因。
+func feedEntry(res RunResult) feed.Entry {
+	e := feed.Entry{ID: feedEntryIDPrefix + res.TraceID, Updated: res.FinishedAt}
+	at := res.StartedAt.Format(feedTimeFormat)
+	switch {
+	case res.Err != nil:
+		e.Title = fmt.Sprintf("%s 运行失败", at)
+		e.HTML = "<p>" + html.EscapeString(res.Err.Error()) + "</p>"
+	case len(res.Selected) == 0:
+		e.Title = fmt.Sprintf("%s 无入选", at)
+		e.HTML = fmt.Sprintf("<p>初选 %d 只，技术面通过 %d 只，无入选。</p>", res.Candidates, res.Passed)
+	default:
+		e.Title = fmt.Sprintf("%s 入选 %d 只", at, len(res.Selected))
+		e.HTML = feedTableHTML(res)
+	}
+	return e
+}
+
+func feedTableHTML(res RunResult) string {
+	sel := export.NewSelector(mailFields(), feedDefaultColumns...)
+	var b strings.Builder
+	b.WriteString("<table><thead><tr>")
+	for _, h := range sel.Headers() {
+		b.WriteString("<th>" + html.EscapeString(h) + "</th>")
+	}
+	b.WriteString("</tr></thead><tbody>")
+	for _, s := range res.Selected {
+		if s == nil {
+			continue
+		}
+		b.WriteString("<tr>")
+		for _, c := range sel {
+			v := c.Text(s)
+			if v == "" {
+				v = feedEmptyCell
+			}
+			b.WriteString("<td>" + html.EscapeString(v) + "</td>")
+		}
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</tbody></table>")
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"stockMaxWin/internal/store"
+	"stockMaxWin/internal/trace"
+)
+
+// 入选记录库：STOCKMAXWIN_SQLITE_PATH 非空时每轮推送后把入选及指标快照写入该 SQLite 文件
+const envSQLitePath = "STOCKMAXWIN_SQLITE_PATH"
+
+// saveStoreIfEnabled 开启入选记录库时写入本轮入选，失败只记日志。每轮打开、写完即关，不常驻连接。
+func saveStoreIfEnabled(ctx context.Context, res RunResult) {
+	path := os.Getenv(envSQLitePath)
+	if path == "" || len(res.Selected) == 0 {
+		return
+	}
+	st, err := store.Open(path)
+	if err != nil {
+		trace.Log(ctx, "main: 打开入选记录库失败 path=%s err=%v", path, err)
+		return
+	}
+	defer st.Close()
+	if err := st.Save(ctx, res.StartedAt, res.Selected); err != nil {
+		trace.Log(ctx, "main: 写入选记录库失败 err=%v", err)
+		return
+	}
+	trace.Log(ctx, "main: 已写入选记录库 %d 条 -> %s", len(res.Selected), path)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/mail"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/notify"
+	"stockMaxWin/internal/pipeline"
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// 流水线顺序：STOCKMAXWIN_PIPELINE 为逗号分隔的阶段名，未配置或含未知阶段时用默认顺序。
+// STOCKMAXWIN_INCLUDE_SUSPENDED_VOLUME=1 时停牌日也参与量能指标计算（默认剔除）。
+const (
+	envPipeline               = "STOCKMAXWIN_PIPELINE"
+	envIncludeSuspendedVolume = "STOCKMAXWIN_INCLUDE_SUSPENDED_VOLUME"
+)
+
+func includeSuspendedVolume() bool {
+	s := os.Getenv(envIncludeSuspendedVolume)
+	return s == "1" || s == "true"
+}
+
+// 阶段名
+const (
+	stagePreFilter = "prefilter"
+	stageIndustry  = "industry"
+	stageEnrich    = "enrich"
+	stageFilter    = "filter"
+	stageCooldown  = "cooldown"
+	stageRank      = "rank"
+	stageLimit     = "limit"
+	stageNotify    = "notify"
+)
+
+var defaultPipelineOrder = []string{
+	stagePreFilter, stageIndustry, stageEnrich, stageFilter, stageCooldown, stageRank, stageLimit, stageNotify,
+}
+
+// buildPipeline 按配置组装本轮流水线；各阶段把漏斗统计写入 res。
+func buildPipeline(ctx context.Context, res *RunResult) pipeline.Pipeline {
+	available := availableStages(ctx, res)
+	order := defaultPipelineOrder
+	if s := strings.TrimSpace(os.Getenv(envPipeline)); s != "" {
+		order = strings.Split(s, ",")
+	}
+	p, err := pipeline.Build(order, available)
+	if err != nil {
+		trace.Log(ctx, "main: 流水线配置无效，使用默认顺序 err=%v", err)
+		p, _ = pipeline.Build(defaultPipelineOrder, available)
+	}
+	trace.Log(ctx, "main: 流水线 %s", strings.Join(p.Names(), " -> "))
+	return p
+}
+
+func availableStages(ctx context.Context, res *RunResult) map[string]pipeline.Stage {
+	key, desc, topN := sortKeyFromEnv(), sortDescFromEnv(), topNFromEnv()
+	stages := []pipeline.Stage{
+		pipeline.New(stagePreFilter, func(ctx context.Context, st *pipeline.State) error {
+			if watchlistEnabled() {
+				// 自选股是人工挑过的池子，不再按基本面、成交量门槛初选与截断，全部交给策略判断
+				st.Candidates = append([]model.StockQuote(nil), st.Quotes...)
+				res.Quotes, res.Candidates = len(st.Quotes), len(st.Candidates)
+				trace.Log(ctx, "main: 自选股 %d 只跳过初选，对全部请求 K 线", len(st.Candidates))
+				return nil
+			}
+			candidates := make([]model.StockQuote, 0, len(st.Quotes)/candidateCapDiv)
+			thresholds := strategyThresholds(ctx)
+			opts := filter.PreFilterOptions{Boards: preFilterBoards(), Thresholds: &thresholds}
+			for i := range st.Quotes {
+				if filter.QuotePreFilterWithOptions(&st.Quotes[i], opts) {
+					candidates = append(candidates, st.Quotes[i])
+				}
+			}
+			trace.Log(ctx, "main: 初选 %s %d 只 -> 基本面+成交量 %d 只", scanLabel(), len(st.Quotes), len(candidates))
+			candidates = rankAndTruncate(ctx, candidates)
+			st.Candidates = candidates
+			res.Quotes, res.Candidates = len(st.Quotes), len(candidates)
+			trace.Log(ctx, "main: 对 %d 只候选请求 K 线", len(candidates))
+			return nil
+		}),
+		pipeline.New(stageIndustry, func(ctx context.Context, st *pipeline.State) error {
+			annotateIndustryHeat(ctx, st.Candidates)
+			annotateConceptHeat(ctx, st.Candidates)
+			return nil
+		}),
+		pipeline.New(stageEnrich, func(ctx context.Context, st *pipeline.State) error {
+			st.Stocks, res.Failures, res.Stats = enrichCandidates(ctx, st.Candidates, strategyKlineCount())
+			if len(res.Failures) > 0 {
+				trace.Log(ctx, "main: 补全指标失败 %d 只 %s", len(res.Failures), worker.FormatFailureCounts(res.Failures))
+			}
+			return nil
+		}),
+		pipeline.New(stageFilter, func(ctx context.Context, st *pipeline.State) error {
+			strategy := strategyFilter(ctx)
+			passed := st.Stocks[:0]
+			for _, s := range st.Stocks {
+				if !strategy(s) {
+					continue
+				}
+				passed = append(passed, s)
+				fmt.Fprintf(os.Stdout, "%s %s 主营=%s 现价=%.2f 涨跌幅=%.2f%%\n",
+					s.Code, s.Name, s.MainBusiness, s.Price, s.ChangePct)
+			}
+			// 补全时 Pool 放行全部，淘汰与通过数在策略过滤这里补记
+			res.Stats.Filtered += len(st.Stocks) - len(passed)
+			res.Stats.Selected = len(passed)
+			st.Stocks = passed
+			res.Passed = len(passed)
+			return nil
+		}),
+		pipeline.New(stageCooldown, func(ctx context.Context, st *pipeline.State) error {
+			st.Stocks = applyCooldown(ctx, st.Stocks)
+			return nil
+		}),
+		pipeline.New(stageRank, func(ctx context.Context, st *pipeline.State) error {
+			st.Stocks = rankStocks(st.Stocks, key, desc, 0)
+			return nil
+		}),
+		pipeline.New(stageLimit, func(ctx context.Context, st *pipeline.State) error {
+			if len(st.Stocks) > topN {
+				st.Stocks = st.Stocks[:topN]
+			}
+			worker.FillProfiles(ctx, apiClient, st.Stocks)
+			trace.Log(ctx, "main: 选股完成，%s取前 %d 只", key.label(desc), len(st.Stocks))
+			return nil
+		}),
+		pipeline.New(stageNotify, func(ctx context.Context, st *pipeline.State) error {
+			if len(st.Stocks) == 0 {
+				trace.Log(ctx, "main: 无选中股票，按设计不推送（正常）")
+				return nil
+			}
+			now, mode := clock(), pushDedupeMode()
+			push := pushedToday.dedupe(ctx, now, st.Stocks, mode)
+			if len(push) == 0 {
+				trace.Log(ctx, "main: 本轮入选今日均已推送过，不再重复推送")
+				return nil
+			}
+			channels := notifiers
+			if mailCfg := buildMailConfig(config.LoadSMTP()); mailCfg.Enabled() {
+				channels = append([]notify.Notifier{mailNotifier{cfg: mailCfg, opts: mail.ReportOptions{
+					SortLabel:       key.label(desc),
+					TopN:            topN,
+					Columns:         mailFields(),
+					Comment:         llmComment(ctx, push),
+					CallAuction:     res.CallAuction,
+					StrategySummary: strategySummary(ctx),
+					CSVAttachment:   mailCSVEnabled(),
+				}}}, notifiers...)
+			}
+			if len(channels) == 0 {
+				trace.Log(ctx, "main: 未配置 SMTP 或任何推送渠道，跳过推送")
+				return nil
+			}
+			if notify.SendAll(ctx, channels, push) == 0 {
+				trace.Log(ctx, "main: %d 个推送渠道均发送失败，不记为今日已推送，下一轮重试", len(channels))
+				return nil
+			}
+			for _, s := range push {
+				s.Delivered = true
+			}
+			if mode != pushDedupeOff {
+				pushedToday.record(now, push)
+			}
+			return nil
+		}),
+	}
+	m := make(map[string]pipeline.Stage, len(stages))
+	for _, s := range stages {
+		m[s.Name()] = s
+	}
+	return m
+}
+
+// rankAndTruncate 按“量比+换手+涨幅”强度分排序并标注初选排名（PreFilterRank，从 1 开始，随结果透传），
+// 配置了候选上限时取前 n 只，优先保留活跃股。
+func rankAndTruncate(ctx context.Context, candidates []model.StockQuote) []model.StockQuote {
+	cfg := config.LoadStrength()
+	w := filter.DefaultStrengthWeights()
+	if cfg.WeightsSet() {
+		w = filter.StrengthWeights{VolumeRatio: cfg.VolumeRatioWeight, TurnoverRate: cfg.TurnoverRateWeight, ChangePct: cfg.ChangePctWeight}
+	}
+	filter.SortByStrength(candidates, w)
+	for i := range candidates {
+		candidates[i].PreFilterRank = i + 1
+	}
+	if cfg.CandidateTop <= 0 || len(candidates) <= cfg.CandidateTop {
+		return candidates
+	}
+	trace.Log(ctx, "main: 按强度分(量比%.2f 换手%.2f 涨幅%.2f)截断候选 %d -> %d 只",
+		w.VolumeRatio, w.TurnoverRate, w.ChangePct, len(candidates), cfg.CandidateTop)
+	return candidates[:cfg.CandidateTop]
+}
+
+// enrichCandidates 用 worker 池并发拉 K 线、计算指标，不做过滤（过滤由 filter 阶段负责）；
+// 同时返回因无数据、拉取失败等被丢弃的股票及原因。
+// 候选按代码排序后入队，结果与失败列表也按代码排序返回：与列表分页顺序、worker 完成先后无关，每轮日志与排序并列项可比对。
+// klineCount 为每只股票请求的 K 线根数，流水线按配置策略推导（strategyKlineCount），sweep 固定跑趋势动能策略需全部指标。
+// 流水线每轮只补全一次，不挂 worker.StockCache（缓存供同轮内多个 Pool 共用同一批候选时使用）。另返回 Pool 的处理统计（Pool 不做策略过滤，全部补全成功的计入 Selected）。
+func enrichCandidates(ctx context.Context, candidates []model.StockQuote, klineCount int) ([]*model.Stock, []worker.Failure, worker.Stats) {
+	candidates = append([]model.StockQuote(nil), candidates...)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Code < candidates[j].Code })
+	jobs := make(chan model.StockQuote, jobChannelBuffer)
+	results := make(chan *model.Stock, jobChannelBuffer)
+	cfg := worker.DefaultConfig()
+	cfg.Concurrency = concurrency()
+	cfg.IncludeSuspendedVolume = includeSuspendedVolume()
+	cfg.KlineCount = klineCount
+	cfg.FundFlowDays = fundFlowDays()
+	cfg.MoneyFlow = moneyFlowEnabled()
+	cfg.Scorer = sortKeyFromEnv().scorer()
+	cfg.Filter = func(*model.Stock) bool { return true }
+	pool := worker.NewPool(cfg, apiClient, jobs, results)
+
+	var stocks []*model.Stock
+	done := make(chan struct{})
+	go func() {
+		for s := range results {
+			if s != nil {
+				stocks = append(stocks, s)
+			}
+		}
+		close(done)
+	}()
+
+	go pool.Run(ctx)
+
+	for i := range candidates {
+		select {
+		case <-ctx.Done():
+			trace.Log(ctx, "main: ctx done, produced %d jobs", i)
+			goto done
+		case jobs <- candidates[i]:
+		}
+	}
+done:
+	close(jobs)
+	<-done
+	sort.Slice(stocks, func(i, j int) bool { return stocks[i].Code < stocks[j].Code })
+	failures := pool.Failures()
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Code < failures[j].Code })
+	return stocks, failures, pool.Stats()
+}
+
+// mailNotifier 把选股邮件包装成 notify.Notifier，与飞书、钉钉等渠道一起遍历发送；选项随本轮构造（点评、集合竞价标注等）。
+type mailNotifier struct {
+	cfg  *mail.SMTPConfig
+	opts mail.ReportOptions
+}
+
+func (m mailNotifier) Name() string { return "mail" }
+
+func (m mailNotifier) SendReport(ctx context.Context, stocks []*model.Stock) error {
+	return mail.SendReport(ctx, m.cfg, stocks, m.opts)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"stockMaxWin/internal/history"
+	"stockMaxWin/internal/trace"
+)
+
+// 入选历史：STOCKMAXWIN_HISTORY_FILE 非空时每轮入选追加到该 JSONL 文件，stats 命令读取它
+const envHistoryFile = "STOCKMAXWIN_HISTORY_FILE"
+
+// stats 命令默认参数与超时
+const (
+	defaultStatsDays     = 30
+	defaultStatsHoldDays = 5
+	statsKlineBuffer     = 10
+	statsTimeout         = 30 * time.Second
+)
+
+// appendHistoryIfEnabled 开启历史落盘时追加本轮入选，失败只记日志。
+func appendHistoryIfEnabled(ctx context.Context, res RunResult) {
+	path := os.Getenv(envHistoryFile)
+	if path == "" || len(res.Selected) == 0 {
+		return
+	}
+	if err := history.Append(path, res.StartedAt, res.TraceID, res.Selected); err != nil {
+		trace.Log(ctx, "main: 写入选历史失败 path=%s err=%v", path, err)
+		return
+	}
+	trace.Log(ctx, "main: 已追加入选历史 %d 条 -> %s", len(res.Selected), path)
+}
+
+// runStatsCommand 统计单只股票近 days 天的入选次数与入选后 hold 日前瞻收益（以入选当日收盘为基准）。
+// 用法：stockMaxWin stats <代码> [天数=30] [持有日=5]
+func runStatsCommand(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "用法: stockMaxWin stats <代码> [天数=30] [持有日=5]")
+		return 2
+	}
+	code := strings.TrimSpace(args[0])
+	days := intArg(args, 1, defaultStatsDays)
+	hold := intArg(args, 2, defaultStatsHoldDays)
+	path := os.Getenv(envHistoryFile)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "未设置 %s，无入选历史可统计\n", envHistoryFile)
+		return 1
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取入选历史失败: %v\n", err)
+		return 1
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	var runs int
+	var dates []string
+	seen := make(map[string]bool)
+	for _, r := range records {
+		if r.Code != code || r.RunAt.Before(since) {
+			continue
+		}
+		runs++
+		if d := r.Date(); !seen[d] {
+			seen[d] = true
+			dates = append(dates, d)
+		}
+	}
+	fmt.Printf("%s 近 %d 天入选 %d 次（%d 个交易日）\n", code, days, runs, len(dates))
+	if len(dates) == 0 {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+	ctx = trace.WithTraceID(ctx, trace.NewTraceID())
+	klines, err := apiClient.GetHisKlines(ctx, code, days+hold+statsKlineBuffer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "拉取 K 线失败: %v\n", err)
+		return 1
+	}
+	idxByDate := make(map[string]int, len(klines))
+	for i := range klines {
+		idxByDate[klines[i].Date] = i
+	}
+	var sum float64
+	var n, wins int
+	for _, d := range dates {
+		i, ok := idxByDate[d]
+		if !ok {
+			fmt.Printf("  %s  无当日 K 线（非交易日或数据缺失）\n", d)
+			continue
+		}
+		if i+hold >= len(klines) || klines[i].Close <= 0 {
+			fmt.Printf("  %s  收盘 %.2f，未满 %d 个交易日\n", d, klines[i].Close, hold)
+			continue
+		}
+		ret := (klines[i+hold].Close/klines[i].Close - 1) * 100
+		sum += ret
+		n++
+		if ret > 0 {
+			wins++
+		}
+		fmt.Printf("  %s  收盘 %.2f -> %d 日后 %.2f，收益 %.2f%%\n", d, klines[i].Close, hold, klines[i+hold].Close, ret)
+	}
+	if n > 0 {
+		fmt.Printf("入选后 %d 日：样本 %d，平均收益 %.2f%%，胜率 %.1f%%\n", hold, n, sum/float64(n), float64(wins)*100/float64(n))
+	}
+	return 0
+}
+
+func intArg(args []string, i, def int) int {
+	if len(args) <= i {
+		return def
+	}
+	if n, err := strconv.Atoi(args[i]); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"stockMaxWin/internal/trace"
+)
+
+// 单次运行状态文件：STOCKMAXWIN_STATUS_FILE 非空时单次模式结束写 JSON 状态，供外部监控（cron、探针）读取
+const (
+	envStatusFile  = "STOCKMAXWIN_STATUS_FILE"
+	statusFilePerm = 0o644
+	statusDirPerm  = 0o755
+)
+
+// runStatus 状态文件内容，与 /status 的 last_run 字段保持一致并额外给出 success。
+type runStatus struct {
+	TraceID    string    `json:"trace_id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Selected   int       `json:"selected"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func newRunStatus(res RunResult) runStatus {
+	st := runStatus{
+		TraceID:    res.TraceID,
+		StartedAt:  res.StartedAt,
+		FinishedAt: res.FinishedAt,
+		Selected:   len(res.Selected),
+		Success:    res.Err == nil,
+	}
+	if res.Err != nil {
+		st.Error = res.Err.Error()
+	}
+	return st
+}
+
+// writeStatusIfEnabled 配置了状态文件时写出本轮状态，失败只记日志。
+func writeStatusIfEnabled(ctx context.Context, res RunResult) {
+	path := os.Getenv(envStatusFile)
+	if path == "" {
+		return
+	}
+	if err := writeStatusFile(path, newRunStatus(res)); err != nil {
+		trace.Log(ctx, "main: 写状态文件失败 err=%v", err)
+		return
+	}
+	trace.Log(ctx, "main: 已写状态文件 %s", path)
+}
+
+// writeStatusFile 先写同目录临时文件再 rename，监控方不会读到写了一半的 JSON。
+func writeStatusFile(path string, st runStatus) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, statusDirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), statusFilePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"stockMaxWin/internal/config"
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// 策略可选条件：
+// STOCKMAXWIN_CHANGE_PCT_MAX=x 趋势策略涨幅上限(%)，避免选到已涨停的票，默认不限；
+// STOCKMAXWIN_MARKET_CAP_MAX_YI=x 市值上限(亿元)，初选与趋势策略都生效，排除大盘股，默认不限；
+// STOCKMAXWIN_COMPARE_DIGITS=n 比较前把展示类字段舍入到 n 位小数，与邮件展示一致，默认全精度；
+// STOCKMAXWIN_NET_INFLOW_DAYS=n 要求近 n 日主力连续净流入（需额外拉资金流），默认不启用；
+// STOCKMAXWIN_MONEY_FLOW=1 对候选逐只调用资金流专用接口补全主力资金字段（列表字段常为空），默认不启用。
+const (
+	envChangePctMax  = "STOCKMAXWIN_CHANGE_PCT_MAX"
+	envCompareDigits = "STOCKMAXWIN_COMPARE_DIGITS"
+	envMarketCapMax  = "STOCKMAXWIN_MARKET_CAP_MAX_YI"
+	envNetInflowDays = "STOCKMAXWIN_NET_INFLOW_DAYS"
+	envMoneyFlow     = "STOCKMAXWIN_MONEY_FLOW"
+)
+
+// 配置条件中按名引用、需按参数推导拉取量的条件：持续净流入决定资金流天数，创新高决定 K 线根数
+const (
+	criterionContinuousNetInflow = "continuous_net_inflow"
+	criterionNewHighWithin       = "new_high_within"
+)
+
+// yi 亿元
+const yi = 1e8
+
+// marketCapMax 市值上限(元)，未配置或无效返回 0（不限）。
+func marketCapMax() float64 {
+	if s := os.Getenv(envMarketCapMax); s != "" {
+		if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 {
+			return v * yi
+		}
+	}
+	return 0
+}
+
+// compareDigits 比较前舍入位数，未配置或无效返回 -1（全精度）。
+func compareDigits() int {
+	if s := os.Getenv(envCompareDigits); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return -1
+}
+
+func netInflowDays() int {
+	if s := os.Getenv(envNetInflowDays); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// fundFlowDays worker 需拉取的资金流天数：取环境变量与配置条件 continuous_net_inflow 中较大者，0 不拉取。
+func fundFlowDays() int {
+	n := netInflowDays()
+	if p := config.LoadCriteria()[criterionContinuousNetInflow]; len(p) == 1 && int(p[0]) > n {
+		n = int(p[0])
+	}
+	return n
+}
+
+// moneyFlowEnabled 是否对候选调用资金流专用接口。
+func moneyFlowEnabled() bool {
+	s := os.Getenv(envMoneyFlow)
+	return s == "1" || s == "true"
+}
+
+func changePctMax() float64 {
+	if s := os.Getenv(envChangePctMax); s != "" {
+		if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// strategyThresholds 趋势动能阈值：内置默认值 <- strategy.json 中已配置的字段 <- 环境变量（涨幅上限、市值上限）。
+// 初选与策略共用，避免放宽策略阈值后初选仍按默认值误杀。
+func strategyThresholds(ctx context.Context) filter.Thresholds {
+	t := filter.DefaultThresholds()
+	sc, err := config.LoadStrategy()
+	if err != nil {
+		trace.Log(ctx, "main: 策略阈值文件无效，使用默认阈值 err=%v", err)
+	}
+	for _, f := range []struct {
+		src *float64
+		dst *float64
+	}{
+		{sc.MarketCapMin, &t.MarketCapMin},
+		{sc.MarketCapMax, &t.MarketCapMax},
+		{sc.PEMin, &t.PEMin},
+		{sc.PEMax, &t.PEMax},
+		{sc.TurnoverMin, &t.TurnoverMin},
+		{sc.TurnoverMax, &t.TurnoverMax},
+		{sc.VolumeRatioMin, &t.VolumeRatioMin},
+		{sc.ChangePctMin, &t.ChangePctMin},
+		{sc.ChangePctMax, &t.ChangePctMax},
+		{sc.AmountMin, &t.AmountMin},
+		{sc.NetInflowMin, &t.NetInflowMin},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if v := changePctMax(); v > 0 {
+		t.ChangePctMax = v
+	}
+	if v := marketCapMax(); v > 0 {
+		t.MarketCapMax = v
+	}
+	return t
+}
+
+// strategySummary 邮件中的策略说明：配置了条件时只注明按配置条件筛选，否则按当前生效阈值描述趋势动能策略。
+func strategySummary(ctx context.Context) string {
+	if specs := config.LoadCriteria(); len(specs) > 0 {
+		return fmt.Sprintf("按配置条件筛选（%d 条）", len(specs))
+	}
+	return strategyThresholds(ctx).Summary()
+}
+
+// strategyFilter 当前策略：配置文件 criteria 段按名构造；未配置或构造失败时用趋势动能（阈值见 strategyThresholds）。
+// 配置了行业热度时再叠加 IndustryRankTop；配置了比较精度时整体按舍入后的值判断。
+func strategyFilter(ctx context.Context) filter.Criterion {
+	c := configuredCriteria(ctx)
+	if c == nil {
+		c = filter.TrendMomentumStrategyFrom(strategyThresholds(ctx))
+	}
+	if n := industryTopN(); n > 0 {
+		c = filter.And(c, filter.IndustryRankTop(n))
+	}
+	if n := netInflowDays(); n > 0 {
+		c = filter.And(c, filter.ContinuousNetInflow(n))
+	}
+	if d := compareDigits(); d >= 0 {
+		trace.Log(ctx, "main: 比较前舍入到 %d 位小数", d)
+		c = filter.Rounded(c, d)
+	}
+	return c
+}
+
+// criterionIndicators 按名引用的条件所依赖的指标，用于推导 K 线数量；未列出的条件只用列表数据或最少根数的 K 线即可（如 up_streak_min）。
+var criterionIndicators = map[string][]string{
+	"price_above_ma5":        {worker.IndicatorMA5},
+	"ma5_above_ma10":         {worker.IndicatorMA5, worker.IndicatorMA10},
+	"price_above_ma20":       {worker.IndicatorMA20},
+	"bullish_ma_alignment":   {worker.IndicatorMA5, worker.IndicatorMA10, worker.IndicatorMA20, worker.IndicatorMA60},
+	"ma_alignment":           {worker.IndicatorMA5, worker.IndicatorMA10, worker.IndicatorMA20, worker.IndicatorMA60},
+	"ma60_up":                {worker.IndicatorMA60Up},
+	"ma20_cross_up_ma60":     {worker.IndicatorMACross},
+	"macd_histogram_grow":    {worker.IndicatorMACD},
+	"macd_golden_cross":      {worker.IndicatorMACD},
+	"macd_momentum":          {worker.IndicatorMACD},
+	"drawdown_range":         {worker.IndicatorDrawdown},
+	"rsi_range":              {worker.IndicatorRSI14},
+	"price_break_boll_upper": {worker.IndicatorBoll},
+	"volume_surge":           {worker.IndicatorVolMA5},
+	"atr_pct_max":            {worker.IndicatorATR14},
+	"new_high_within":        {worker.IndicatorNewHigh},
+}
+
+// strategyKlineCount 按当前策略启用的指标推导 K 线数量；未配置条件（或配置无效回退内置策略）时按全部指标。
+func strategyKlineCount() int {
+	specs := config.LoadCriteria()
+	if len(specs) == 0 {
+		return worker.KlineCountFor(worker.AllIndicators()...)
+	}
+	params := make(map[string]filter.Params, len(specs))
+	var indicators []string
+	for name, p := range specs {
+		params[name] = filter.Params(p)
+		indicators = append(indicators, criterionIndicators[name]...)
+	}
+	if _, err := filter.BuildAll(params); err != nil {
+		return worker.KlineCountFor(worker.AllIndicators()...)
+	}
+	n := worker.KlineCountFor(indicators...)
+	if p := params[criterionNewHighWithin]; len(p) == 1 && int(p[0]) > n {
+		n = int(p[0])
+	}
+	return n
+}
+
+func configuredCriteria(ctx context.Context) filter.Criterion {
+	specs := config.LoadCriteria()
+	if len(specs) == 0 {
+		return nil
+	}
+	params := make(map[string]filter.Params, len(specs))
+	for name, p := range specs {
+		params[name] = filter.Params(p)
+	}
+	c, err := filter.BuildAll(params)
+	if err != nil {
+		trace.Log(ctx, "main: 配置条件构造失败，回退内置策略 err=%v", err)
+		return nil
+	}
+	trace.Log(ctx, "main: 使用配置条件 %d 条", len(specs))
+	return c
+}
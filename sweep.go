@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"stockMaxWin/internal/filter"
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+	"stockMaxWin/internal/worker"
+)
+
+// sweepParams 可扫描的阈值参数，名称与 strategy.json 字段一致（市值单位为元）。
+var sweepParams = map[string]func(t *filter.Thresholds, v float64){
+	"market_cap_min":   func(t *filter.Thresholds, v float64) { t.MarketCapMin = v },
+	"market_cap_max":   func(t *filter.Thresholds, v float64) { t.MarketCapMax = v },
+	"pe_min":           func(t *filter.Thresholds, v float64) { t.PEMin = v },
+	"pe_max":           func(t *filter.Thresholds, v float64) { t.PEMax = v },
+	"turnover_min":     func(t *filter.Thresholds, v float64) { t.TurnoverMin = v },
+	"turnover_max":     func(t *filter.Thresholds, v float64) { t.TurnoverMax = v },
+	"volume_ratio_min": func(t *filter.Thresholds, v float64) { t.VolumeRatioMin = v },
+	"change_pct_min":   func(t *filter.Thresholds, v float64) { t.ChangePctMin = v },
+	"change_pct_max":   func(t *filter.Thresholds, v float64) { t.ChangePctMax = v },
+	"amount_min":       func(t *filter.Thresholds, v float64) { t.AmountMin = v },
+	"net_inflow_min":   func(t *filter.Thresholds, v float64) { t.NetInflowMin = v },
+}
+
+// sweepValue 某一取值下的阈值与结果。
+type sweepValue struct {
+	value      float64
+	thresholds filter.Thresholds
+	selected   []string // 入选代码，升序
+}
+
+// runSweepCommand 阈值敏感性分析：对一个参数扫一组取值，行情与 K 线只拉一次，各取值分别跑初选 + 趋势动能策略，
+// 输出每个取值的入选数及相对上一取值的增减。以当前生效阈值（默认值 <- strategy.json <- 环境变量）为基准，
+// 不含候选截断、冷却、配置条件与行业/资金流叠加条件，只看该阈值本身的影响。
+// 用法：stockMaxWin sweep <参数> <取值1,取值2,...>
+func runSweepCommand(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "用法: stockMaxWin sweep <参数> <取值1,取值2,...>\n可选参数: %s\n", strings.Join(sweepParamNames(), ", "))
+		return 2
+	}
+	set, ok := sweepParams[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知参数 %q，可选: %s\n", args[0], strings.Join(sweepParamNames(), ", "))
+		return 2
+	}
+	values, err := parseSweepValues(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "取值无效: %v\n", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+	ctx = trace.WithTraceID(ctx, trace.NewTraceID())
+	quotes, err := fetchQuotes(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "拉取%s行情失败: %v\n", scanLabel(), err)
+		return 1
+	}
+	base := strategyThresholds(ctx)
+	boards := preFilterBoards()
+	sweeps := make([]sweepValue, len(values))
+	passed := make([]map[string]bool, len(values))
+	union := make(map[string]bool)
+	var candidates []model.StockQuote
+	for i, v := range values {
+		t := base
+		set(&t, v)
+		sweeps[i] = sweepValue{value: v, thresholds: t}
+		passed[i] = make(map[string]bool)
+		opts := filter.PreFilterOptions{Boards: boards, Thresholds: &sweeps[i].thresholds}
+		for j := range quotes {
+			if !filter.QuotePreFilterWithOptions(&quotes[j], opts) {
+				continue
+			}
+			passed[i][quotes[j].Code] = true
+			if !union[quotes[j].Code] {
+				union[quotes[j].Code] = true
+				candidates = append(candidates, quotes[j])
+			}
+		}
+	}
+	fmt.Printf("%s行情 %d 只，各取值初选并集 %d 只，拉取 K 线中...\n", scanLabel(), len(quotes), len(candidates))
+	// 始终跑内置趋势动能策略（需要 MA60 等），K 线根数不随配置条件缩减
+	stocks, failures, _ := enrichCandidates(ctx, candidates, worker.KlineCountFor(worker.AllIndicators()...))
+	if len(failures) > 0 {
+		fmt.Printf("补全指标失败 %d 只，不参与比较\n", len(failures))
+	}
+
+	for i := range sweeps {
+		strategy := filter.TrendMomentumStrategyFrom(sweeps[i].thresholds)
+		for _, s := range stocks {
+			if passed[i][s.Code] && strategy(s) {
+				sweeps[i].selected = append(sweeps[i].selected, s.Code)
+			}
+		}
+		sort.Strings(sweeps[i].selected)
+	}
+	printSweep(args[0], sweeps)
+	return 0
+}
+
+func printSweep(param string, sweeps []sweepValue) {
+	fmt.Printf("\n%-12s %6s  相对上一取值\n", param, "入选数")
+	for i, sv := range sweeps {
+		line := fmt.Sprintf("%-12s %6d", strconv.FormatFloat(sv.value, 'f', -1, 64), len(sv.selected))
+		if i > 0 {
+			added, removed := diffCodes(sweeps[i-1].selected, sv.selected)
+			line += fmt.Sprintf("  +%d -%d", len(added), len(removed))
+			if len(added) > 0 {
+				line += " 新增 " + strings.Join(added, ",")
+			}
+			if len(removed) > 0 {
+				line += " 移除 " + strings.Join(removed, ",")
+			}
+		}
+		fmt.Println(line)
+	}
+}
+
+// diffCodes 返回 cur 相对 prev 新增与移除的代码（均升序）。
+func diffCodes(prev, cur []string) (added, removed []string) {
+	in := func(list []string, code string) bool {
+		i := sort.SearchStrings(list, code)
+		return i < len(list) && list[i] == code
+	}
+	for _, c := range cur {
+		if !in(prev, c) {
+			added = append(added, c)
+		}
+	}
+	for _, c := range prev {
+		if !in(cur, c) {
+			removed = append(removed, c)
+		}
+	}
+	return added, removed
+}
+
+func parseSweepValues(s string) ([]float64, error) {
+	var out []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("至少需要一个取值")
+	}
+	return out, nil
+}
+
+func sweepParamNames() []string {
+	names := make([]string, 0, len(sweepParams))
+	for name := range sweepParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"stockMaxWin/internal/model"
+	"stockMaxWin/internal/trace"
+)
+
+// 自选股模式：STOCKMAXWIN_WATCHLIST 为代码列表文件路径（"-" 为标准输入），配置后不再拉全量行情，
+// 只按代码查这些股票的行情，跳过初选（基本面、成交量门槛与候选截断）直接补全指标、跑策略并照常推送。
+// 文件每行一个或多个代码（逗号、空白分隔），# 之后为注释；代码可带 sh/sz/bj 前缀或 .SH/.SZ/.BJ 后缀。
+// 文件每轮重新读取，调度运行中改文件下一轮生效；标准输入只读一次。
+const (
+	envWatchlist   = "STOCKMAXWIN_WATCHLIST"
+	watchlistStdin = "-"
+	stockCodeLen   = 6
+)
+
+// watchlistStdinCodes 标准输入只能读一次，调度模式下后续轮次复用首次读到的代码。
+var watchlistStdinCodes struct {
+	once  sync.Once
+	codes []string
+	err   error
+}
+
+// watchlistPath 自选股文件路径，空表示未启用（按扫描范围拉全量）。
+func watchlistPath() string {
+	return strings.TrimSpace(os.Getenv(envWatchlist))
+}
+
+func watchlistEnabled() bool {
+	return watchlistPath() != ""
+}
+
+// loadWatchlist 读取自选股代码（去重、保持原顺序），无效代码只记日志跳过。
+func loadWatchlist(ctx context.Context) ([]string, error) {
+	path := watchlistPath()
+	if path == watchlistStdin {
+		watchlistStdinCodes.once.Do(func() {
+			watchlistStdinCodes.codes, watchlistStdinCodes.err = parseWatchlist(ctx, os.Stdin)
+		})
+		return watchlistStdinCodes.codes, watchlistStdinCodes.err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseWatchlist(ctx, f)
+}
+
+func parseWatchlist(ctx context.Context, r io.Reader) ([]string, error) {
+	var codes []string
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == '，' || r == ' ' || r == '\t' || r == '\r'
+		}) {
+			code, ok := normalizeStockCode(field)
+			if !ok {
+				trace.Log(ctx, "main: 自选股代码 %q 无效，跳过", field)
+				continue
+			}
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes, sc.Err()
+}
+
+// normalizeStockCode 去掉市场前后缀，返回 6 位数字代码，如 sh600519、600519.SH -> 600519。
+func normalizeStockCode(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, m := range []string{"sh", "sz", "bj"} {
+		s = strings.TrimPrefix(s, m)
+		s = strings.TrimSuffix(s, "."+m)
+	}
+	if len(s) != stockCodeLen {
+		return "", false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// fetchWatchlistQuotes 按自选股代码查行情；列表为空或全部查询失败时返回错误。
+func fetchWatchlistQuotes(ctx context.Context) ([]model.StockQuote, error) {
+	codes, err := loadWatchlist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("读取自选股 %s: %w", watchlistPath(), err)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("自选股 %s 中没有有效代码", watchlistPath())
+	}
+	trace.Log(ctx, "main: 自选股模式，共 %d 只，跳过全量行情", len(codes))
+	return apiClient.GetQuotesByCodes(ctx, codes)
+}